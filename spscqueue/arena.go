@@ -0,0 +1,40 @@
+package spscqueue
+
+// Arena holds a set of queues of equal capacity which share a single underlying buffer. Creating
+// many small queues from an arena takes two allocations rather than two per queue, and keeps their
+// elements close together in memory. Each queue keeps its own padded indices, so the queues of an
+// arena are fully independent of each other.
+type Arena[T any] struct {
+	items  []T
+	queues []Queue[T]
+}
+
+// NewArena[T any] returns an arena of `count` empty queues, each with capacity for `sizeEach`
+// elements of type `T`. The Options are applied to every queue. A queue of the arena which is
+// grown using Resize moves to a buffer of its own.
+func NewArena[T any](count, sizeEach uint, opts ...Option) *Arena[T] {
+	n := sizeEach + 1
+	a := &Arena[T]{
+		items:  make([]T, count*n),
+		queues: make([]Queue[T], count),
+	}
+	for i := range a.queues {
+		lo := uint(i) * n
+		// Limit the capacity of the window, so that no queue can reach into its neighbour.
+		a.queues[i].init(a.items[lo:lo+n:lo+n], opts)
+	}
+
+	return a
+}
+
+// Queue returns the `i`-th queue of the arena.
+// Any thread may call Queue.
+func (a *Arena[T]) Queue(i int) *Queue[T] {
+	return &a.queues[i]
+}
+
+// Len returns the number of queues in the arena.
+// Any thread may call Len.
+func (a *Arena[T]) Len() int {
+	return len(a.queues)
+}
@@ -0,0 +1,66 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// Test for using the queues of an arena independently.
+func TestArena(t *testing.T) {
+	const numQueues, numItems = 4, 1000
+	a := NewArena[int](numQueues, 7)
+	if l := a.Len(); l != numQueues {
+		t.Errorf("Unexpected number of queues; %v != %v", l, numQueues)
+	}
+	for i := 0; i < numQueues; i++ {
+		if c := a.Queue(i).Cap(); c != 7 {
+			t.Errorf("Unexpected capacity; %v != 7", c)
+		}
+	}
+
+	// Filling one queue must leave its neighbours untouched.
+	for i := 0; i < 7; i++ {
+		a.Queue(1).Push(i)
+	}
+	if a.Queue(1).Offer(7) {
+		t.Error("Managed to add element to full queue!")
+	}
+	for _, i := range []int{0, 2} {
+		if l := a.Queue(i).Len(); l != 0 {
+			t.Errorf("Unexpected length; %v != 0", l)
+		}
+	}
+	for i := 0; i < 7; i++ {
+		if v := a.Queue(1).Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+
+	// Use a fresh arena, so that each role stays on a single goroutine.
+	a = NewArena[int](numQueues, 7)
+	wg := sync.WaitGroup{}
+	for i := 0; i < numQueues; i++ {
+		q := a.Queue(i)
+		base := i * numItems
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			for j := 0; j < numItems; j++ {
+				q.Push(base + j)
+			}
+		}(&wg)
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			for j := 0; j < numItems; j++ {
+				if v := q.Pop(); v != base+j {
+					t.Errorf("Got incorrect value; %v != %v", v, base+j)
+				}
+			}
+		}(&wg)
+	}
+
+	wg.Wait()
+}
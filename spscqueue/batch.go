@@ -0,0 +1,120 @@
+package spscqueue
+
+import (
+	"fmt"
+)
+
+// BatchReader serves the elements of a queue one at a time, while removing them from the queue in
+// batches. It takes a window of up to `windowSize` elements from the front of the queue using
+// ReadableSpan, and only moves the consumer past them using AdvanceN once all of them have been
+// served, which reduces the number of index updates by up to a factor of the window size. A window
+// ends early at the end of the underlying buffer, or at the back of the queue. Elements which have
+// been served but not yet removed keep their slots occupied, so the producer cannot reuse them;
+// Flush removes them early.
+type BatchReader[T any] struct {
+	q      *Queue[T]
+	size   uint64
+	window []T
+	pos    int
+}
+
+// NewBatchReader returns a BatchReader for the queue which takes windows of up to `windowSize`
+// elements. NewBatchReader panics if `windowSize` is 0.
+// The BatchReader acts as the consumer of the queue; it may only be used by the consumer, which
+// should not use other consumer methods unless it has flushed the BatchReader first.
+func (q *Queue[T]) NewBatchReader(windowSize uint) *BatchReader[T] {
+	if windowSize == 0 {
+		panic(fmt.Sprintf("spscqueue: batch reader with window size %v", windowSize))
+	}
+
+	return &BatchReader[T]{q: q, size: uint64(windowSize)}
+}
+
+// Next returns the next element of the queue, and false if the queue is empty. Next does not
+// block. Once the current window is exhausted, Next removes its elements from the queue before
+// taking the next window.
+func (r *BatchReader[T]) Next() (T, bool) {
+	if r.pos == len(r.window) {
+		r.Flush()
+		span := r.q.ReadableSpan()
+		if uint64(len(span)) > r.size {
+			span = span[:r.size]
+		}
+		if len(span) == 0 {
+			var t T
+			return t, false
+		}
+		r.window = span
+	}
+	el := r.window[r.pos]
+	r.pos++
+
+	return el, true
+}
+
+// Flush removes the elements which Next has served from the queue, and discards the rest of the
+// current window, whose elements remain in the queue to be served again. Flush should be called
+// once the consumer stops using the BatchReader.
+func (r *BatchReader[T]) Flush() {
+	if r.pos > 0 {
+		r.q.AdvanceN(uint64(r.pos))
+	}
+	r.window, r.pos = nil, 0
+}
+
+// BatchWriter adds elements to a queue one at a time, while publishing them to the consumer in
+// batches. It reserves a window of up to `windowSize` free slots at the back of the queue using
+// ReserveN, fills it with the added elements, and only publishes them using CommitN once the window
+// is full, which reduces the number of index updates by up to a factor of the window size. A window
+// ends early at the end of the underlying buffer, or just before the consumer. Elements which have
+// been added but not yet published are invisible to the consumer; Flush publishes them early.
+type BatchWriter[T any] struct {
+	q      *Queue[T]
+	size   uint64
+	window []T
+	pos    int
+}
+
+// NewBatchWriter returns a BatchWriter for the queue which reserves windows of up to `windowSize`
+// slots. NewBatchWriter panics if `windowSize` is 0.
+// The BatchWriter acts as the producer of the queue; it may only be used by the producer, which
+// should not use other producer methods unless it has flushed the BatchWriter first.
+func (q *Queue[T]) NewBatchWriter(windowSize uint) *BatchWriter[T] {
+	if windowSize == 0 {
+		panic(fmt.Sprintf("spscqueue: batch writer with window size %v", windowSize))
+	}
+
+	return &BatchWriter[T]{q: q, size: uint64(windowSize)}
+}
+
+// Add adds the passed element to the current window, and publishes the window once it is full. If
+// no slot is available, Add blocks until the consumer frees one, like Push.
+func (w *BatchWriter[T]) Add(el T) {
+	if w.pos == len(w.window) {
+		w.window = w.q.ReserveN(w.size)
+		for len(w.window) == 0 {
+			w.q.WaitForSpace(1)
+			w.window = w.q.ReserveN(w.size)
+		}
+	}
+	w.window[w.pos] = el
+	w.pos++
+	if w.pos == len(w.window) {
+		w.Flush()
+	}
+}
+
+// Flush publishes the elements added to the current window, and releases the rest of the window.
+// Flush should be called once the producer stops using the BatchWriter.
+func (w *BatchWriter[T]) Flush() {
+	if w.pos > 0 {
+		w.q.CommitN(uint64(w.pos))
+	}
+	w.window, w.pos = nil, 0
+}
+
+// Close publishes the elements added to the current window and closes the queue.
+func (w *BatchWriter[T]) Close() {
+	w.Flush()
+	w.q.Close()
+}
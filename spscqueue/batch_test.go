@@ -0,0 +1,152 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// Test that a BatchReader serves elements in order and removes them in batches.
+func TestBatchReader(t *testing.T) {
+	q := New[int](16)
+
+	// Move the indices so that the elements wrap around the end of the buffer.
+	for i := 0; i < 14; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(14)
+
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	r := q.NewBatchReader(4)
+
+	// The first window is cut short by the end of the buffer.
+	for _, c := range []struct {
+		want   int
+		length uint64
+	}{
+		{0, 10}, {1, 10}, {2, 10}, {3, 7}, {4, 7}, {5, 7}, {6, 7}, {7, 3}, {8, 3},
+	} {
+		v, ok := r.Next()
+		if !ok || v != c.want {
+			t.Errorf("Got incorrect value; %v != %v", v, c.want)
+		}
+		if l := q.Len(); l != c.length {
+			t.Errorf("Unexpected length after %v; %v != %v", c.want, l, c.length)
+		}
+	}
+
+	r.Flush()
+	if l := q.Len(); l != 1 {
+		t.Errorf("Unexpected length; %v != 1", l)
+	}
+	if v, ok := q.Front(); !ok || v != 9 {
+		t.Errorf("Got incorrect value; %v != 9", v)
+	}
+	if v, ok := r.Next(); !ok || v != 9 {
+		t.Errorf("Got incorrect value; %v != 9", v)
+	}
+	if _, ok := r.Next(); ok {
+		t.Error("Got element from empty queue")
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// SPSC test for the BatchReader.
+func TestBatchReaderPushPop(t *testing.T) {
+	const numItems = 10000
+	q := New[int](61)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			q.Push(i)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		r := q.NewBatchReader(8)
+		defer r.Flush()
+		for i := 0; i < numItems; i++ {
+			v, ok := r.Next()
+			for !ok {
+				runtime.Gosched()
+				v, ok = r.Next()
+			}
+			if v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+}
+
+// Test that a BatchWriter publishes elements in batches.
+func TestBatchWriter(t *testing.T) {
+	q := New[int](16)
+
+	// Move the indices so that the elements wrap around the end of the buffer.
+	for i := 0; i < 14; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(14)
+
+	w := q.NewBatchWriter(4)
+	// The first window is cut short by the end of the buffer.
+	for i, length := range []uint64{0, 0, 3, 3, 3, 3, 7, 7, 7, 7, 11} {
+		w.Add(i)
+		if l := q.Len(); l != length {
+			t.Errorf("Unexpected length after %v; %v != %v", i, l, length)
+		}
+	}
+
+	w.Close()
+	if l := q.Len(); l != 11 {
+		t.Errorf("Unexpected length; %v != 11", l)
+	}
+	if !q.Closed() {
+		t.Error("Queue not closed")
+	}
+	for i := 0; i < 11; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+}
+
+// SPSC test for the BatchWriter.
+func TestBatchWriterPushPop(t *testing.T) {
+	const numItems = 10000
+	q := New[int](61)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		w := q.NewBatchWriter(8)
+		for i := 0; i < numItems; i++ {
+			w.Add(i)
+		}
+		w.Close()
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+}
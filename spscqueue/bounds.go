@@ -0,0 +1,36 @@
+package spscqueue
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// checkAvailable panics unless at least `n` elements are available to the consumer. It is called
+// by the methods that move the consumer forward once its cached copy of the producer's position
+// shows fewer than `n` elements, and refreshes that copy once before giving up, so that a caller
+// which is misusing the queue cannot move the consumer past the producer and read garbage.
+// checkAvailable should be called by the consumer.
+func (q *Queue[T]) checkAvailable(n uint64, method string) {
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	if avail := q.length(q.rIdx, q.wIdxCached); n > avail {
+		panic(fmt.Sprintf("spscqueue: %v past the back of the queue; advancing %v with %v available",
+			method, n, avail))
+	}
+}
+
+// PeekAt returns the element `i` positions after the front of the queue without removing it, such
+// that PeekAt(0) returns the same element as Front. A boolean indicator of success or failure is
+// included as a second return value; PeekAt fails if fewer than `i+1` elements are in the queue.
+// PeekAt should be called by the consumer.
+func (q *Queue[T]) PeekAt(i uint64) (T, bool) {
+	q.checkConsumer()
+	if i >= q.length(q.rIdx, q.wIdxCached) {
+		q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+		if i >= q.length(q.rIdx, q.wIdxCached) {
+			var t T
+			return t, false
+		}
+	}
+
+	return q.items[q.slot(q.add(q.rIdx, i))], true
+}
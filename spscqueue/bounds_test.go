@@ -0,0 +1,72 @@
+package spscqueue
+
+import (
+	"strings"
+	"testing"
+)
+
+// expectPanic fails the test unless `f` panics with a message containing `substr`.
+func expectPanic(t *testing.T, substr string, f func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		r := recover()
+		if r == nil {
+			t.Errorf("Expected panic containing %q", substr)
+		} else if msg, ok := r.(string); !ok || !strings.Contains(msg, substr) {
+			t.Errorf("Unexpected panic; %v", r)
+		}
+	}()
+	f()
+}
+
+// Test for PeekAt with offsets within and beyond the queued elements.
+func TestPeekAt(t *testing.T) {
+	q := New[int](4)
+	if _, ok := q.PeekAt(0); ok {
+		t.Error("PeekAt succeeded on an empty queue")
+	}
+
+	// Wrap the queued elements around the end of the buffer.
+	for i := 0; i < 3; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+	for i := 0; i < 4; i++ {
+		q.Push(i)
+	}
+	for i := uint64(0); i < 4; i++ {
+		if v, ok := q.PeekAt(i); !ok || v != int(i) {
+			t.Errorf("Got incorrect value; %v, %v != %v, true", v, ok, i)
+		}
+	}
+	for _, i := range []uint64{4, 5, 1 << 63} {
+		if v, ok := q.PeekAt(i); ok || v != 0 {
+			t.Errorf("PeekAt succeeded beyond the queued elements; %v, %v", v, ok)
+		}
+	}
+	if l := q.Len(); l != 4 {
+		t.Errorf("Unexpected length; %v != 4", l)
+	}
+}
+
+// Test that moving the consumer past the producer panics and leaves the queue intact.
+func TestAdvanceBounds(t *testing.T) {
+	q := New[int](4)
+	expectPanic(t, "Advance past the back of the queue; advancing 1 with 0 available", q.Advance)
+
+	q.Push(1)
+	q.Push(2)
+	expectPanic(t, "AdvanceN past the back of the queue; advancing 3 with 2 available", func() { q.AdvanceN(3) })
+	expectPanic(t, "advancing 18446744073709551615 with 2", func() { q.AdvanceN(^uint64(0)) })
+	if l := q.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+
+	q.AdvanceN(2)
+	expectPanic(t, "Advance past the back of the queue", q.Advance)
+	q.Push(3)
+	if v := q.Pop(); v != 3 {
+		t.Errorf("Got incorrect value; %v != 3", v)
+	}
+}
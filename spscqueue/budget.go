@@ -0,0 +1,27 @@
+package spscqueue
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// NewForBytes[T any] returns an empty queue with the largest capacity whose memory footprint, as
+// reported by SizeBytes, does not exceed `maxBytes`. The footprint covers the queue structure and
+// the underlying buffer, including the slot which is always kept free; bookkeeping allocated by
+// Options such as WithLazyFill or WithSpinInstrumentation is not accounted for. NewForBytes returns
+// an error if `maxBytes` does not suffice for a capacity of at least one element, or if `T` has a
+// size of zero.
+func NewForBytes[T any](maxBytes uintptr, opts ...Option) (*Queue[T], error) {
+	var zero T
+	elSize := unsafe.Sizeof(zero)
+	if elSize == 0 {
+		return nil, fmt.Errorf("spscqueue: cannot size queue of zero-sized %T by bytes", zero)
+	}
+	overhead := unsafe.Sizeof(Queue[T]{})
+	if maxBytes < overhead+2*elSize {
+		return nil, fmt.Errorf("spscqueue: budget of %v bytes too small for a queue of %T",
+			maxBytes, zero)
+	}
+
+	return New[T](uint((maxBytes-overhead)/elSize-1), opts...), nil
+}
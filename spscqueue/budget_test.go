@@ -0,0 +1,34 @@
+package spscqueue
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// Test that queues sized by bytes stay within their budget.
+func TestNewForBytes(t *testing.T) {
+	type element [3]uint64
+	overhead := unsafe.Sizeof(Queue[element]{})
+	for _, budget := range []uintptr{1 << 10, 1 << 16, overhead + 2*24, overhead + 100} {
+		q, err := NewForBytes[element](budget)
+		if err != nil {
+			t.Errorf("Failed to create queue for %v bytes; %v", budget, err)
+			continue
+		}
+		if s := q.SizeBytes(); s > budget {
+			t.Errorf("Queue exceeds budget; %v > %v", s, budget)
+		}
+		// One more element must not have fit.
+		if s := New[element](uint(q.Cap()) + 1).SizeBytes(); s <= budget {
+			t.Errorf("Queue of capacity %v not the largest within budget; %v <= %v",
+				q.Cap(), s, budget)
+		}
+	}
+
+	if _, err := NewForBytes[element](overhead + 2*24 - 1); err == nil {
+		t.Error("Created queue without room for an element")
+	}
+	if _, err := NewForBytes[struct{}](1 << 10); err == nil {
+		t.Error("Created queue of zero-sized elements")
+	}
+}
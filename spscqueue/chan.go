@@ -0,0 +1,47 @@
+package spscqueue
+
+// Chan returns a channel which delivers the elements of the queue in order, so that the queue can
+// be used in a select statement alongside other channels. The first call to Chan starts a goroutine
+// which takes on the consumer role of the queue, and forwards each element to the channel before
+// removing it from the queue, such that the queue still exerts back-pressure on the producer.
+// Once the queue has been closed and drained, the goroutine closes the channel and exits; it keeps
+// running for as long as the queue remains open. Subsequent calls to Chan return the same channel.
+// Once Chan has been called, no other consumer methods may be used.
+// Any thread may call Chan.
+func (q *Queue[T]) Chan() <-chan T {
+	q.chanOnce.Do(func() {
+		q.ch = make(chan T)
+		go q.forward()
+	})
+
+	return q.ch
+}
+
+// forward sends the elements of the queue on the channel returned by Chan until the queue has been
+// closed and drained, and then closes the channel.
+// forward should be called by the consumer.
+func (q *Queue[T]) forward() {
+	defer close(q.ch)
+
+	var spins uint64
+	for {
+		el, ok := q.Front()
+		if !ok {
+			// The producer closes the queue after its last push, so re-check for elements after
+			// observing the closure.
+			if q.Closed() {
+				if el, ok = q.Front(); !ok {
+					return
+				}
+			} else {
+				q.waitStrategy().Wait(spins)
+				spins++
+				continue
+			}
+		}
+		spins = 0
+
+		q.ch <- el
+		q.Advance()
+	}
+}
@@ -0,0 +1,51 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for selecting across the channels of two queues.
+func TestChan(t *testing.T) {
+	const numItems = 1000
+	queues := []*Queue[int]{New[int](4), New[int](16)}
+	for _, q := range queues {
+		go func(q *Queue[int]) {
+			for i := 0; i < numItems; i++ {
+				q.Push(i)
+			}
+			q.Close()
+		}(q)
+	}
+
+	a, b := queues[0].Chan(), queues[1].Chan()
+	if a2 := queues[0].Chan(); a2 != a {
+		t.Error("Got different channel from subsequent call")
+	}
+	var next [2]int
+	for a != nil || b != nil {
+		var v, src int
+		var ok bool
+		select {
+		case v, ok = <-a:
+			if !ok {
+				a = nil
+				continue
+			}
+		case v, ok = <-b:
+			if !ok {
+				b = nil
+				continue
+			}
+			src = 1
+		}
+		if v != next[src] {
+			t.Errorf("Got incorrect value; %v != %v", v, next[src])
+		}
+		next[src]++
+	}
+	for src, n := range next {
+		if n != numItems {
+			t.Errorf("Unexpected number of elements from queue %v; %v != %v", src, n, numItems)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package spscqueue
+
+import (
+	"fmt"
+)
+
+// Checkpoint returns the read and write indices of the queue, as interpreted by Positions, such
+// that they can later be reinstated using Restore. Contrary to Positions, the indices form a
+// consistent pair.
+// Checkpoint may only be called while neither the producer nor the consumer is operating on the
+// queue.
+func (q *Queue[T]) Checkpoint() (rIdx, wIdx uint64) {
+	return q.rIdx, q.wIdx
+}
+
+// Restore moves the read and write indices of the queue to `rIdx` and `wIdx`, e.g. as returned by
+// Checkpoint. Only the indices are restored; the slots between them are presented to the consumer
+// with whatever contents they hold at the time. Outstanding reservations are discarded. Restore
+// returns an error, leaving the queue unchanged, if either index is out of range for the queue or
+// if the distance between them exceeds its capacity. This allows driving a queue into specific
+// states, e.g. just before or after the indices wrap around, in tests.
+// Restore may only be called while neither the producer nor the consumer is operating on the
+// queue.
+func (q *Queue[T]) Restore(rIdx, wIdx uint64) error {
+	if q.period != 0 && (rIdx >= q.period || wIdx >= q.period) {
+		return fmt.Errorf("spscqueue: restoring indices %v and %v beyond the period %v", rIdx,
+			wIdx, q.period)
+	}
+	if n := q.length(rIdx, wIdx); n > q.Cap() {
+		return fmt.Errorf("spscqueue: restoring indices %v and %v implying %v elements in queue "+
+			"of capacity %v", rIdx, wIdx, n, q.Cap())
+	}
+
+	if q.completed != nil {
+		q.completed = make([]bool, len(q.items))
+	}
+	q.setIndices(rIdx, wIdx)
+	q.pubSeq = q.resSeq
+
+	return nil
+}
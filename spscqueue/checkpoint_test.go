@@ -0,0 +1,101 @@
+package spscqueue
+
+import (
+	"math"
+	"testing"
+)
+
+// Test for restoring a checkpointed state in which the elements wrap around.
+func TestCheckpointRestore(t *testing.T) {
+	q := New[int](4)
+	for i := 0; i < 3; i++ {
+		q.Push(-1)
+		q.Pop()
+	}
+	for i := 0; i < 3; i++ {
+		q.Push(i)
+	}
+
+	rIdx, wIdx := q.Checkpoint()
+	if rIdx != 3 || wIdx != 1 {
+		t.Errorf("Got incorrect indices; %v, %v != 3, 1", rIdx, wIdx)
+	}
+
+	// Mutate the queue, then return to the checkpoint. The elements added after the checkpoint lie
+	// beyond the restored write index.
+	q.Push(3)
+	if err := q.Restore(rIdx, wIdx); err != nil {
+		t.Fatalf("Failed to restore checkpoint; %v", err)
+	}
+	if l := q.Len(); l != 3 {
+		t.Errorf("Unexpected length; %v != 3", l)
+	}
+	for _, want := range []int{0, 1, 2} {
+		if v := q.Pop(); v != want {
+			t.Errorf("Got incorrect value; %v != %v", v, want)
+		}
+	}
+
+	// Restore a nearly full state and fill it up.
+	if err := q.Restore(4, 2); err != nil {
+		t.Fatalf("Failed to restore indices; %v", err)
+	}
+	if !q.Offer(5) {
+		t.Error("Failed to fill up restored queue")
+	}
+	if q.Offer(6) {
+		t.Error("Added element to full restored queue")
+	}
+}
+
+// Test that inconsistent indices are rejected.
+func TestRestoreInvalid(t *testing.T) {
+	q := New[int](4)
+	q.Push(1)
+	for _, tc := range []struct {
+		rIdx, wIdx uint64
+	}{{0, 5}, {5, 0}, {math.MaxUint64, 0}} {
+		if err := q.Restore(tc.rIdx, tc.wIdx); err == nil {
+			t.Errorf("Restored invalid indices %v, %v", tc.rIdx, tc.wIdx)
+		}
+	}
+	if v := q.Pop(); v != 1 {
+		t.Errorf("Got incorrect value; %v != 1", v)
+	}
+
+	// Monotonic indices may take on any value within their period.
+	q = New[int](7, WithMonotonicIndices())
+	if err := q.Restore(math.MaxUint64-1, 2); err != nil {
+		t.Errorf("Failed to restore indices across the wrap-around; %v", err)
+	}
+	if l := q.Len(); l != 4 {
+		t.Errorf("Unexpected length; %v != 4", l)
+	}
+	if err := q.Restore(math.MaxUint64-4, 4); err == nil {
+		t.Error("Restored indices exceeding the capacity")
+	}
+}
+
+// Test that Restore discards slots committed out of order before it.
+func TestRestoreReserveSlot(t *testing.T) {
+	q := New[int](4)
+	q.ReserveSlot()
+	_, seq, _ := q.ReserveSlot()
+	// The second slot is committed, but not published while the first is outstanding.
+	q.CommitUpTo(seq)
+	if err := q.Restore(0, 0); err != nil {
+		t.Fatalf("Failed to restore queue; %v", err)
+	}
+
+	p, seq, ok := q.ReserveSlot()
+	if !ok {
+		t.Fatal("Failed to reserve slot in empty queue")
+	}
+	*p = 1
+	// The second slot is reserved again, but not committed.
+	q.ReserveSlot()
+	q.CommitUpTo(seq)
+	if l := q.Len(); l != 1 {
+		t.Errorf("Unexpected length; %v != 1", l)
+	}
+}
@@ -0,0 +1,84 @@
+package spscqueue
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	// ErrFull is returned when an element could not be added because the queue is full.
+	ErrFull = errors.New("spscqueue: queue is full")
+	// ErrClosed is returned when an element could not be added because the queue is closed.
+	ErrClosed = errors.New("spscqueue: queue is closed")
+)
+
+// Close marks the queue as closed, indicating that the producer will not add any more elements.
+// Elements already in the queue remain available to the consumer. Once the queue is closed, Offer
+// and OfferE no longer add elements. Push and the Reserve-Commit pattern do not check whether the
+// queue is closed, so as to keep their fast path minimal; using them after Close is a programming
+// error. If the queue was created using WithEventfd, Close signals the eventfd, so that a waiting
+// consumer observes the closure.
+// Close should be called by the producer.
+func (q *Queue[T]) Close() {
+	q.checkProducer()
+	atomic.StoreUint32(&q.closed, 1)
+	if q.event != nil {
+		q.event.signal()
+	}
+}
+
+// Closed reports whether Close has been called.
+// Any thread may call Closed.
+func (q *Queue[T]) Closed() bool {
+	return atomic.LoadUint32(&q.closed) != 0
+}
+
+// OfferE adds the passed element to the queue if there is an available slot and the queue has not
+// been closed. OfferE returns nil if the item was added successfully, ErrClosed if the queue is
+// closed and ErrFull if the queue is full.
+// OfferE should be called by the producer.
+func (q *Queue[T]) OfferE(el T) error {
+	if q.closed != 0 {
+		q.recordDrop()
+		return ErrClosed
+	}
+	if !q.Offer(el) {
+		return ErrFull
+	}
+
+	return nil
+}
+
+// Flush blocks until the consumer has removed all elements from the queue.
+// Flush should be called by the producer.
+func (q *Queue[T]) Flush() {
+	q.checkProducer()
+	var spins uint64
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	for q.rIdxCached != q.wIdx {
+		q.waitStrategy().Wait(spins)
+		spins++
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	}
+}
+
+// FlushReport is a variant of Flush which returns the number of elements that were in the queue
+// when FlushReport was called, all of which the consumer has removed by the time it returns. This
+// lends itself to reporting the size of the backlog drained at shutdown.
+// FlushReport should be called by the producer.
+func (q *Queue[T]) FlushReport() uint64 {
+	q.checkProducer()
+	n := q.length(atomic.LoadUint64(&q.rIdx), q.wIdx)
+	q.Flush()
+
+	return n
+}
+
+// CloseAndFlush closes the queue and then blocks until the consumer has removed all elements from
+// it. The queue is closed before waiting, so a consumer which drains the queue until it observes
+// that it is closed and empty terminates.
+// CloseAndFlush should be called by the producer.
+func (q *Queue[T]) CloseAndFlush() {
+	q.Close()
+	q.Flush()
+}
@@ -0,0 +1,114 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test for the outcomes of OfferE.
+func TestOfferE(t *testing.T) {
+	q := New[int](2)
+	if err := q.OfferE(1); err != nil {
+		t.Errorf("Unexpected error; %v", err)
+	}
+	if err := q.OfferE(2); err != nil {
+		t.Errorf("Unexpected error; %v", err)
+	}
+	if err := q.OfferE(3); err != ErrFull {
+		t.Errorf("Unexpected error; %v != %v", err, ErrFull)
+	}
+
+	if q.Closed() {
+		t.Error("Queue closed before calling Close")
+	}
+	q.Close()
+	if !q.Closed() {
+		t.Error("Queue not closed after calling Close")
+	}
+	q.Pop()
+	if err := q.OfferE(3); err != ErrClosed {
+		t.Errorf("Unexpected error; %v != %v", err, ErrClosed)
+	}
+	if q.Offer(3) {
+		t.Error("Managed to add element to closed queue!")
+	}
+
+	// Elements added before closing remain available.
+	if v, ok := q.Front(); !ok || v != 2 {
+		t.Errorf("Got incorrect value; %v != 2", v)
+	}
+}
+
+// Test that CloseAndFlush returns only once the consumer has drained the queue.
+func TestCloseAndFlush(t *testing.T) {
+	const numItems = 100
+	q := New[int](numItems)
+	var consumed int64
+	done := make(chan struct{})
+
+	for i := 0; i < numItems; i++ {
+		q.Push(i)
+	}
+
+	go func() {
+		defer close(done)
+		for {
+			v, ok := q.Front()
+			if !ok {
+				if q.Closed() && q.Len() == 0 {
+					return
+				}
+				runtime.Gosched()
+				continue
+			}
+			time.Sleep(10 * time.Microsecond)
+			if v != int(atomic.LoadInt64(&consumed)) {
+				t.Errorf("Got incorrect value; %v != %v", v, consumed)
+			}
+			atomic.AddInt64(&consumed, 1)
+			q.Advance()
+		}
+	}()
+
+	q.CloseAndFlush()
+	if n := atomic.LoadInt64(&consumed); n != numItems {
+		t.Errorf("CloseAndFlush returned before the queue was drained; %v != %v", n, numItems)
+	}
+	if !q.Closed() {
+		t.Error("Queue not closed after CloseAndFlush")
+	}
+	<-done
+}
+
+// Test that FlushReport reports the backlog drained by a slow consumer.
+func TestFlushReport(t *testing.T) {
+	const numItems = 20
+	q := New[int](numItems)
+	if n := q.FlushReport(); n != 0 {
+		t.Errorf("Unexpected number of flushed elements; %v != 0", n)
+	}
+
+	for i := 0; i < numItems; i++ {
+		q.Push(i)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < numItems; i++ {
+			time.Sleep(100 * time.Microsecond)
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}()
+
+	if n := q.FlushReport(); n != numItems {
+		t.Errorf("Unexpected number of flushed elements; %v != %v", n, numItems)
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+	<-done
+}
@@ -0,0 +1,31 @@
+package spscqueue
+
+import (
+	"runtime"
+)
+
+// Consume removes elements from the queue and passes them to `handle` until `done` is closed.
+// Consume blocks while the queue is empty. Once it observes that `done` is closed, Consume passes
+// every element remaining in the queue to `handle` before returning, so no element pushed before
+// `done` was closed is lost.
+// Consume acts as the consumer of the queue; no other goroutine may take on that role while
+// Consume is running.
+func Consume[T any](q *Queue[T], handle func(T), done <-chan struct{}) {
+	for {
+		if v, ok := q.Front(); ok {
+			q.Advance()
+			handle(v)
+			continue
+		}
+		if isDone(done) {
+			break
+		}
+		runtime.Gosched()
+	}
+
+	// Elements pushed before done was closed are guaranteed to be visible now.
+	for v, ok := q.Front(); ok; v, ok = q.Front() {
+		q.Advance()
+		handle(v)
+	}
+}
@@ -0,0 +1,48 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test that Consume handles every element pushed before done is closed exactly once.
+func TestConsume(t *testing.T) {
+	const numItems = 10000
+	q := New[int](256)
+	done := make(chan struct{})
+	handled := make([]int, numItems)
+	var next int
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		Consume(q, func(v int) {
+			if v != next {
+				t.Errorf("Got incorrect value; %v != %v", v, next)
+			}
+			next++
+			handled[v]++
+			if v%1000 == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}, done)
+	}(&wg)
+
+	for i := 0; i < numItems; i++ {
+		q.Push(i)
+	}
+	// Signal done while the consumer still has buffered elements to work through.
+	close(done)
+	wg.Wait()
+
+	for i, n := range handled {
+		if n != 1 {
+			t.Errorf("Element %v handled %v times", i, n)
+		}
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
@@ -0,0 +1,189 @@
+package spscqueue
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ctxCheckInterval is the number of waits between checks of the context in the context-aware
+// methods.
+const ctxCheckInterval = 32
+
+// The context-aware methods only fetch the done channel of the context once they have to wait, since
+// Done may allocate for some context implementations. An operation which can complete immediately
+// therefore does not touch the context at all.
+//
+// The methods wait using the wait strategy even for queues created using WithFutex, since a
+// goroutine parked on the futex cannot observe the context. They add and remove elements through
+// Push and Pop, which take an overflow queue set through WithOverflow into account and wake a peer
+// parked on the futex or waiting on the eventfd.
+
+// WaitNotEmptyContext blocks until the queue holds at least one element, without removing it, so
+// that the consumer may inspect the queue using Front or ReadableSpan before deciding what to
+// remove. WaitNotEmptyContext returns nil once an element is available, or the error of `ctx` if
+// it is done first. To keep the wait loop cheap, `ctx` is only checked every few waits. For a
+// queue with an overflow queue, WaitNotEmptyContext also returns once the overflow queue holds an
+// element, which Pop and PopContext then remove, but which Front does not see.
+// WaitNotEmptyContext should be called by the consumer.
+func (q *Queue[T]) WaitNotEmptyContext(ctx context.Context) error {
+	q.checkConsumer()
+	if q.rIdx != q.wIdxCached {
+		return nil
+	}
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	if q.rIdx != q.wIdxCached || q.overflowNotEmpty() {
+		return nil
+	}
+
+	done := ctx.Done()
+	var spins uint64
+	for q.rIdx == q.wIdxCached && !q.overflowNotEmpty() {
+		if spins%ctxCheckInterval == 0 && isDone(done) {
+			return ctx.Err()
+		}
+		q.waitStrategy().Wait(spins)
+		spins++
+		q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	}
+
+	return nil
+}
+
+// overflowNotEmpty reports whether the queue has an overflow queue which holds an element.
+// overflowNotEmpty should be called by the consumer.
+func (q *Queue[T]) overflowNotEmpty() bool {
+	sec := q.overflow
+	return sec != nil && sec.rIdx != atomic.LoadUint64(&sec.wIdx)
+}
+
+// PopContext is a variant of Pop which gives up waiting for an element once `ctx` is done.
+// PopContext returns the removed element and nil, or the zero-value for the type and the error of
+// `ctx` if it is done before an element becomes available. An available element takes precedence
+// over a done context.
+// PopContext should be called by the consumer.
+func (q *Queue[T]) PopContext(ctx context.Context) (T, error) {
+	if err := q.WaitNotEmptyContext(ctx); err != nil {
+		var t T
+		return t, err
+	}
+
+	return q.Pop(), nil
+}
+
+// PushContext is a variant of Push which gives up waiting for a free slot once `ctx` is done.
+// PushContext returns nil once the element was added, or the error of `ctx` if it is done before
+// a slot becomes available, in which case the element is not added. A free slot takes precedence
+// over a done context. For a queue with an overflow queue, PushContext only waits once both are
+// full, like Push.
+// PushContext should be called by the producer.
+func (q *Queue[T]) PushContext(ctx context.Context, el T) error {
+	q.checkProducer()
+	// Push only blocks on the overflow queue, once it holds elements and is full.
+	w := q
+	if q.overflow != nil {
+		w = q.overflow
+	}
+	if err := w.awaitSlotContext(ctx); err != nil {
+		return err
+	}
+	q.Push(el)
+
+	return nil
+}
+
+// awaitSlotContext blocks until the slot at the back of the queue is free, or `ctx` is done, in
+// which case it returns the error of `ctx`.
+// awaitSlotContext should be called by the producer.
+func (q *Queue[T]) awaitSlotContext(ctx context.Context) error {
+	wIdxNext := q.next(q.wIdx)
+	if !q.collides(wIdxNext, q.rIdxCached) {
+		return nil
+	}
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	if !q.collides(wIdxNext, q.rIdxCached) {
+		return nil
+	}
+
+	done := ctx.Done()
+	var spins uint64
+	for q.collides(wIdxNext, q.rIdxCached) {
+		if spins%ctxCheckInterval == 0 && isDone(done) {
+			return ctx.Err()
+		}
+		q.waitStrategy().Wait(spins)
+		spins++
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	}
+
+	return nil
+}
+
+// PushBatchContext adds the elements of `els` to the queue in order, waiting for free slots as
+// necessary, until all of them have been added or `ctx` is done. The elements are added using
+// PushSlice, as many at a time as fit, so the consumer only ever observes fully written elements.
+// PushBatchContext returns the number of elements added, along with nil, the error of `ctx` if it
+// is done before all elements were added, or ErrClosed if the queue has been closed. Free slots
+// take precedence over a done context. For a queue with an overflow queue, the elements are added
+// one at a time using PushContext instead, so that they are placed like Push places them.
+// PushBatchContext should be called by the producer.
+func (q *Queue[T]) PushBatchContext(ctx context.Context, els []T) (int, error) {
+	if q.overflow != nil {
+		for i, el := range els {
+			if q.closed != 0 {
+				return i, ErrClosed
+			} else if err := q.PushContext(ctx, el); err != nil {
+				return i, err
+			}
+		}
+		return len(els), nil
+	}
+
+	var done <-chan struct{}
+	var waited bool
+	var added int
+	var spins uint64
+	for len(els) > 0 {
+		left := q.PushSlice(els)
+		if n := len(els) - len(left); n > 0 {
+			added += n
+			els = left
+			spins = 0
+			continue
+		} else if q.closed != 0 {
+			return added, ErrClosed
+		}
+
+		if !waited {
+			done, waited = ctx.Done(), true
+		}
+		if spins%ctxCheckInterval == 0 && isDone(done) {
+			return added, ctx.Err()
+		}
+		q.waitStrategy().Wait(spins)
+		spins++
+	}
+
+	return added, nil
+}
+
+// Limiter paces the consumer in PopRateLimited. Wait blocks until the caller may proceed, or
+// returns an error, typically the error of `ctx` once it is done. *rate.Limiter of
+// golang.org/x/time/rate implements Limiter.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// PopRateLimited waits on `limiter` and then removes the oldest element of the queue like
+// PopContext, such that a consumer loop calling PopRateLimited removes elements no faster than
+// `limiter` allows. PopRateLimited returns the removed element and nil, or the zero-value for the
+// type and the error of `limiter` or of `ctx` if either wait fails, in which case no element is
+// removed. A permission obtained from `limiter` is not returned if the wait for an element fails.
+// PopRateLimited should be called by the consumer.
+func (q *Queue[T]) PopRateLimited(limiter Limiter, ctx context.Context) (T, error) {
+	if err := limiter.Wait(ctx); err != nil {
+		var t T
+		return t, err
+	}
+
+	return q.PopContext(ctx)
+}
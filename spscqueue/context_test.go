@@ -0,0 +1,285 @@
+package spscqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for waiting on an element with a context.
+func TestWaitNotEmptyContext(t *testing.T) {
+	q := New[int](4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond)
+		cancel()
+	}()
+	if err := q.WaitNotEmptyContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error; %v != %v", err, context.Canceled)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		q.Push(1)
+	}()
+	if err := q.WaitNotEmptyContext(context.Background()); err != nil {
+		t.Errorf("Unexpected error; %v", err)
+	}
+	if l := q.Len(); l != 1 {
+		t.Errorf("Unexpected length; %v != 1", l)
+	}
+
+	// An available element takes precedence over a cancelled context.
+	if err := q.WaitNotEmptyContext(ctx); err != nil {
+		t.Errorf("Unexpected error; %v", err)
+	}
+}
+
+// Test for popping and pushing with a context.
+func TestPopPushContext(t *testing.T) {
+	q := New[int](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if v, err := q.PopContext(ctx); !errors.Is(err, context.Canceled) || v != 0 {
+		t.Errorf("Unexpected result; %v, %v != 0, %v", v, err, context.Canceled)
+	}
+	// A free slot and an available element take precedence over a cancelled context.
+	if err := q.PushContext(ctx, 1); err != nil {
+		t.Errorf("Unexpected error; %v", err)
+	}
+	if err := q.PushContext(ctx, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error; %v != %v", err, context.Canceled)
+	}
+	if v, err := q.PopContext(ctx); err != nil || v != 1 {
+		t.Errorf("Got incorrect value; %v, %v != 1, nil", v, err)
+	}
+
+	// Wait for a producer on another goroutine.
+	q = New[int](1)
+	go func() {
+		time.Sleep(time.Millisecond)
+		q.Push(3)
+	}()
+	if v, err := q.PopContext(context.Background()); err != nil || v != 3 {
+		t.Errorf("Got incorrect value; %v, %v != 3, nil", v, err)
+	}
+}
+
+// Test that the context-aware methods take an overflow queue into account.
+func TestContextOverflow(t *testing.T) {
+	q := New[int](1)
+	q.WithOverflow(New[int](2))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 1; i <= 3; i++ {
+		if err := q.PushContext(ctx, i); err != nil {
+			t.Errorf("Unexpected error; %v", err)
+		}
+	}
+	if err := q.PushContext(ctx, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error; %v != %v", err, context.Canceled)
+	}
+	for i := 1; i <= 3; i++ {
+		if v, err := q.PopContext(ctx); err != nil || v != i {
+			t.Errorf("Got incorrect value; %v, %v != %v, nil", v, err, i)
+		}
+	}
+
+	n, err := q.PushBatchContext(ctx, []int{5, 6, 7, 8})
+	if n != 3 || !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected result; %v, %v != 3, %v", n, err, context.Canceled)
+	}
+	for i := 5; i <= 7; i++ {
+		if v, err := q.PopContext(ctx); err != nil || v != i {
+			t.Errorf("Got incorrect value; %v, %v != %v, nil", v, err, i)
+		}
+	}
+	if err := q.WaitNotEmptyContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error; %v != %v", err, context.Canceled)
+	}
+}
+
+// Test that PushContext wakes a consumer parked on the futex.
+func TestPushContextFutex(t *testing.T) {
+	q := New[int](1, WithFutex())
+	popped := make(chan int)
+	go func() {
+		popped <- q.Pop()
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	if err := q.PushContext(context.Background(), 1); err != nil {
+		t.Errorf("Unexpected error; %v", err)
+	}
+	select {
+	case v := <-popped:
+		if v != 1 {
+			t.Errorf("Got incorrect value; %v != 1", v)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Parked consumer was not woken")
+	}
+}
+
+// allocatingContext is a context whose Done method allocates on every call.
+type allocatingContext struct {
+	context.Context
+}
+
+func (*allocatingContext) Done() <-chan struct{} {
+	return make(chan struct{})
+}
+
+// Test that the context-aware methods do not allocate when they need not wait.
+func TestContextNoAllocs(t *testing.T) {
+	if debugBuild {
+		t.Skip("The role checks of the spscqueue_debug build tag allocate")
+	}
+	q := New[int](4)
+	ctx := &allocatingContext{context.Background()}
+	if n := testing.AllocsPerRun(100, func() { _ = ctx.Done() }); n == 0 {
+		t.Fatal("Context does not allocate in Done")
+	}
+
+	n := testing.AllocsPerRun(100, func() {
+		if err := q.PushContext(ctx, 1); err != nil {
+			t.Errorf("Unexpected error; %v", err)
+		}
+		if err := q.WaitNotEmptyContext(ctx); err != nil {
+			t.Errorf("Unexpected error; %v", err)
+		}
+		if _, err := q.PopContext(ctx); err != nil {
+			t.Errorf("Unexpected error; %v", err)
+		}
+	})
+	if n != 0 {
+		t.Errorf("Unexpected number of allocations; %v != 0", n)
+	}
+}
+
+// SPSC test for cancelling a batch of elements being pushed.
+func TestPushBatchContext(t *testing.T) {
+	els := make([]int, 100)
+	for i := range els {
+		els[i] = i
+	}
+	q := New[int](4)
+	if n, err := q.PushBatchContext(context.Background(), els[:3]); n != 3 || err != nil {
+		t.Errorf("Unexpected result; %v, %v != 3, nil", n, err)
+	}
+
+	q = New[int](4)
+	ctx, cancel := context.WithCancel(context.Background())
+	added := make(chan int)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		n, err := q.PushBatchContext(ctx, els)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Unexpected error; %v != %v", err, context.Canceled)
+		}
+		added <- n
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		// Stop consuming, such that the producer runs out of free slots.
+		for i := 0; i < 10; i++ {
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+		cancel()
+		n := <-added
+		if n < 10 || n >= len(els) {
+			t.Errorf("Unexpected number of elements added; %v", n)
+		}
+		for i := 10; i < n; i++ {
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+		if l := q.Len(); l != 0 {
+			t.Errorf("Unexpected length; %v != 0", l)
+		}
+	}(&wg)
+
+	wg.Wait()
+
+	q = New[int](4)
+	q.Close()
+	if n, err := q.PushBatchContext(context.Background(), els); n != 0 || !errors.Is(err, ErrClosed) {
+		t.Errorf("Unexpected result; %v, %v != 0, %v", n, err, ErrClosed)
+	}
+}
+
+// intervalLimiter is a Limiter which lets callers proceed at most once per interval.
+type intervalLimiter struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	timer := time.NewTimer(l.next.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		l.next = l.next.Add(l.interval)
+		return nil
+	}
+}
+
+// Test that popping with a rate limit bounds the throughput.
+func TestPopRateLimited(t *testing.T) {
+	const numItems = 10
+	const interval = 5 * time.Millisecond
+	q := New[int](numItems)
+	for i := 0; i < numItems; i++ {
+		q.Push(i)
+	}
+
+	limiter := &intervalLimiter{interval: interval}
+	start := time.Now()
+	for i := 0; i < numItems; i++ {
+		if v, err := q.PopRateLimited(limiter, context.Background()); err != nil || v != i {
+			t.Errorf("Got incorrect value; %v, %v != %v, nil", v, err, i)
+		}
+	}
+	if d := time.Since(start); d < (numItems-1)*interval {
+		t.Errorf("Popped elements too fast; %v < %v", d, (numItems-1)*interval)
+	}
+
+	// A failed wait on either the limiter or the queue does not remove an element.
+	q.Push(numItems)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.PopRateLimited(limiter, ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error; %v != %v", err, context.Canceled)
+	}
+	if l := q.Len(); l != 1 {
+		t.Errorf("Unexpected length; %v != 1", l)
+	}
+	q.Pop()
+	ctx, cancel = context.WithTimeout(context.Background(), 3*interval)
+	defer cancel()
+	if _, err := q.PopRateLimited(limiter, ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error; %v != %v", err, context.DeadlineExceeded)
+	}
+}
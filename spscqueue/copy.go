@@ -0,0 +1,44 @@
+package spscqueue
+
+// CopyN moves up to `n` bytes from `src` to `dst` and returns the number of bytes moved. The bytes
+// are copied directly between the contiguous spans of both queues, without an intermediate buffer;
+// each iteration moves as much as the shorter of the two spans allows, so the queues may wrap
+// around independently. CopyN blocks while `src` is empty or `dst` is full, using the wait strategy
+// of the respective queue. It returns fewer than `n` bytes only if `src` is closed and drained.
+// CopyN should be called by the consumer of `src`, which must also be the producer of `dst`.
+func CopyN(dst, src *Queue[byte], n int) int {
+	var moved int
+	var srcSpins, dstSpins uint64
+	for moved < n {
+		in := src.ReadableSpan()
+		if len(in) == 0 {
+			// The producer closes the queue after its last write, so re-check for data after
+			// observing the closure.
+			if src.Closed() && len(src.ReadableSpan()) == 0 {
+				break
+			}
+			src.waitStrategy().Wait(srcSpins)
+			srcSpins++
+			continue
+		}
+		srcSpins = 0
+
+		out := dst.WritableSpan()
+		if len(out) == 0 {
+			dst.waitStrategy().Wait(dstSpins)
+			dstSpins++
+			continue
+		}
+		dstSpins = 0
+
+		if rem := n - moved; len(in) > rem {
+			in = in[:rem]
+		}
+		m := copy(out, in)
+		dst.CommitN(uint64(m))
+		src.AdvanceN(uint64(m))
+		moved += m
+	}
+
+	return moved
+}
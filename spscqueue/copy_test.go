@@ -0,0 +1,79 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// Test for copying a payload which wraps around in both queues.
+func TestCopyN(t *testing.T) {
+	src := New[byte](8)
+	dst := New[byte](10)
+
+	// Move the indices such that the payload wraps around at different positions in each queue.
+	for i := 0; i < 5; i++ {
+		src.Push(0)
+	}
+	src.AdvanceN(5)
+	for i := 0; i < 7; i++ {
+		dst.Push(0)
+	}
+	dst.AdvanceN(7)
+
+	const payload = "abcdefgh"
+	for _, c := range []byte(payload) {
+		src.Push(c)
+	}
+	if n := CopyN(dst, src, 6); n != 6 {
+		t.Errorf("Unexpected number of bytes copied; %v != 6", n)
+	}
+	if l := src.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+	src.Close()
+	if n := CopyN(dst, src, 6); n != 2 {
+		t.Errorf("Unexpected number of bytes copied from closed queue; %v != 2", n)
+	}
+
+	for _, c := range []byte(payload) {
+		if v := dst.Pop(); v != c {
+			t.Errorf("Got incorrect value; %v != %v", v, c)
+		}
+	}
+}
+
+// SPSC test for streaming bytes through a pair of queues.
+func TestCopyNStream(t *testing.T) {
+	const numItems = 10000
+	src := New[byte](61)
+	dst := New[byte](37)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			src.Push(byte(i))
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		if n := CopyN(dst, src, numItems); n != numItems {
+			t.Errorf("Unexpected number of bytes copied; %v != %v", n, numItems)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if v := dst.Pop(); v != byte(i) {
+				t.Errorf("Got incorrect value; %v != %v", v, byte(i))
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+}
@@ -0,0 +1,68 @@
+package spscqueue
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// deadlockCheckInterval is the number of waits between checks of the elapsed time in blocked
+// operations of queues created using WithDeadlockDetection.
+const deadlockCheckInterval = 16
+
+// WithDeadlockDetection makes a blocked Push or Pop, and the operations building on them, panic if
+// it is likely never to return because the producer and the consumer are driven from the same
+// goroutine: a Push on a full queue then waits for a consumer which cannot run, and a Pop on an
+// empty queue for a producer which cannot run. In builds using the spscqueue_debug build tag, the
+// goroutines bound to the roles are known, so a blocked operation panics as soon as it finds that
+// the other role is bound to its own goroutine. Otherwise, a blocked operation panics once it has
+// waited for `timeout` while the other side has never been seen to make any progress, i.e. to move
+// its index, since the queue was created. The other side having moved its index once shows that it
+// runs on a goroutine of its own, so a slow or idle consumer or producer does not trip the
+// detection after that; conversely, a goroutine which drove both sides before blocking is not
+// detected. The timeout is not enforced while the goroutine is parked using WithFutex.
+func WithDeadlockDetection(timeout time.Duration) Option {
+	return func(o *options) {
+		o.deadlockTimeout = timeout
+	}
+}
+
+// checkDeadlock implements WithDeadlockDetection for an operation of `role` which has been waiting
+// on the other side for `spins` waits, since `blocked`, which it sets on the first wait.
+func (q *Queue[T]) checkDeadlock(role Role, spins uint64, blocked *time.Time) {
+	op, state, other := "Push", "full", "consumer"
+	if role == RoleConsumer {
+		op, state, other = "Pop", "empty", "producer"
+	}
+
+	if spins == 0 {
+		if q.boundToCaller(role) {
+			panic(fmt.Sprintf("spscqueue: single-goroutine deadlock: %v blocked on %v queue from "+
+				"the goroutine bound to the %v role; the producer and the consumer must run on "+
+				"different goroutines", op, state, other))
+		}
+		q.peerMoved(role)
+		*blocked = time.Now()
+	} else if spins%deadlockCheckInterval == 0 {
+		if d := time.Since(*blocked); d > q.deadlock && !q.peerMoved(role) {
+			panic(fmt.Sprintf("spscqueue: likely single-goroutine deadlock: %v blocked on %v queue "+
+				"for %v without the %v ever making progress; the producer and the consumer must run on "+
+				"different goroutines", op, state, d, other))
+		}
+	}
+}
+
+// peerMoved reports whether the side opposite to `role` has ever been seen moving its index, as
+// observed by the calls to peerMoved from `role`.
+// peerMoved should be called by `role`.
+func (q *Queue[T]) peerMoved(role Role) bool {
+	idx, seen, moved := &q.rIdx, &q.producerPeer, &q.producerMoved
+	if role == RoleConsumer {
+		idx, seen, moved = &q.wIdx, &q.consumerPeer, &q.consumerMoved
+	}
+	if v := atomic.LoadUint64(idx); v != *seen {
+		*seen, *moved = v, true
+	}
+
+	return *moved
+}
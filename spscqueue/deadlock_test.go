@@ -0,0 +1,84 @@
+package spscqueue
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for detecting a blocking push and pop from the goroutine driving both sides.
+func TestDeadlockDetection(t *testing.T) {
+	q := New[int](2, WithDeadlockDetection(20*time.Millisecond))
+	q.Push(1)
+	q.Push(2)
+	// The pop uses a queue of its own, since the pushes above are progress of the producer.
+	e := New[int](2, WithDeadlockDetection(20*time.Millisecond))
+	for _, f := range []func(){func() { q.Push(3) }, func() { e.Pop() }} {
+		func() {
+			defer func() {
+				r := recover()
+				if msg, ok := r.(string); !ok || !strings.Contains(msg, "single-goroutine deadlock") {
+					t.Errorf("Unexpected panic; %v", r)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+// SPSC test that waiting on an active other side does not trip the detection.
+func TestDeadlockDetectionActive(t *testing.T) {
+	const numItems = 1000
+	q := New[int](2, WithDeadlockDetection(time.Second))
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			q.Push(i)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if i%100 == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+}
+
+// SPSC test that waiting on a consumer which is slower than the timeout does not trip the detection
+// once the consumer has made progress.
+func TestDeadlockDetectionSlow(t *testing.T) {
+	const numItems = 4
+	const timeout = 5 * time.Millisecond
+	q := New[int](1, WithDeadlockDetection(timeout))
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+			time.Sleep(4 * timeout)
+		}
+	}(&wg)
+
+	// The pushes block for several timeouts each, since the consumer is slow.
+	for i := 0; i < numItems; i++ {
+		q.Push(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,38 @@
+//go:build !spscqueue_debug
+
+package spscqueue
+
+// debugBuild reports whether the spscqueue_debug build tag is used.
+const debugBuild = false
+
+// debugState is empty unless the spscqueue_debug build tag is used.
+type debugState struct{}
+
+// checkProducer is a no-op unless the spscqueue_debug build tag is used.
+func (q *Queue[T]) checkProducer() {}
+
+// checkConsumer is a no-op unless the spscqueue_debug build tag is used.
+func (q *Queue[T]) checkConsumer() {}
+
+// markPreallocated is a no-op unless the spscqueue_debug build tag is used.
+func (q *Queue[T]) markPreallocated() {}
+
+// poisonFront is a no-op unless the spscqueue_debug build tag is used.
+func (q *Queue[T]) poisonFront(uint64) {}
+
+// boundToCaller reports false, since roles are only bound to goroutines if the spscqueue_debug
+// build tag is used.
+func (q *Queue[T]) boundToCaller(Role) bool {
+	return false
+}
+
+// enableDiagnostics is a no-op unless the spscqueue_debug build tag is used.
+func (q *Queue[T]) enableDiagnostics() {}
+
+// DiagInfo returns the call stacks at which the producer and consumer roles were bound to their
+// goroutines. The stacks are only recorded for queues created using WithDiagnostics in builds
+// using the spscqueue_debug build tag, so without the build tag DiagInfo returns empty strings.
+// Any thread may call DiagInfo.
+func (q *Queue[T]) DiagInfo() (producerStack, consumerStack string) {
+	return "", ""
+}
@@ -0,0 +1,175 @@
+//go:build spscqueue_debug
+
+package spscqueue
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// debugBuild reports whether the spscqueue_debug build tag is used.
+const debugBuild = true
+
+// poisonByte is the pattern written into slots freed by the consumer.
+const poisonByte = 0xDE
+
+// debugState records the goroutines which took on the producer and consumer roles, where they did
+// so if diagnostics are enabled, and the value used to poison freed slots. It is only populated in
+// builds using the spscqueue_debug build tag.
+type debugState struct {
+	producer      int64
+	consumer      int64
+	diagnostics   bool
+	producerStack atomic.Value
+	consumerStack atomic.Value
+	preallocated  bool
+	poison        any
+}
+
+// checkProducer binds the producer role to the calling goroutine on first use, and panics if it is
+// later called from a different goroutine.
+func (q *Queue[T]) checkProducer() {
+	q.debug.checkRole("producer", &q.debug.producer, &q.debug.producerStack)
+}
+
+// checkConsumer binds the consumer role to the calling goroutine on first use, and panics if it is
+// later called from a different goroutine.
+func (q *Queue[T]) checkConsumer() {
+	q.debug.checkRole("consumer", &q.debug.consumer, &q.debug.consumerStack)
+}
+
+// checkRole binds the role tracked by `owner` to the calling goroutine, recording the call stack in
+// `stack` if diagnostics are enabled, or panics if the role is already bound to another goroutine.
+func (d *debugState) checkRole(role string, owner *int64, stack *atomic.Value) {
+	id := goroutineID()
+	if atomic.CompareAndSwapInt64(owner, 0, id) {
+		if d.diagnostics {
+			stack.Store(callerStack())
+		}
+		return
+	}
+	if bound := atomic.LoadInt64(owner); bound != id {
+		msg := fmt.Sprintf("spscqueue: %v role of goroutine %v used by goroutine %v", role, bound, id)
+		if d.diagnostics {
+			producer, consumer := d.stacks()
+			msg += fmt.Sprintf("\nproducer role bound at:\n%vconsumer role bound at:\n%v",
+				producer, consumer)
+		}
+		panic(msg)
+	}
+}
+
+// boundToCaller reports whether the role other than `role` is bound to the calling goroutine.
+func (q *Queue[T]) boundToCaller(role Role) bool {
+	other := &q.debug.consumer
+	if role == RoleConsumer {
+		other = &q.debug.producer
+	}
+
+	return atomic.LoadInt64(other) == goroutineID()
+}
+
+// enableDiagnostics makes the queue record where each role was bound.
+func (q *Queue[T]) enableDiagnostics() {
+	q.debug.diagnostics = true
+}
+
+// DiagInfo returns the call stacks at which the producer and consumer roles were bound to their
+// goroutines, i.e. of the first producer and the first consumer operation. The stacks are only
+// recorded for queues created using WithDiagnostics in builds using the spscqueue_debug build tag;
+// otherwise, or before the respective role was bound, empty strings are returned.
+// Any thread may call DiagInfo.
+func (q *Queue[T]) DiagInfo() (producerStack, consumerStack string) {
+	return q.debug.stacks()
+}
+
+// stacks returns the recorded call stacks of the producer and consumer roles.
+func (d *debugState) stacks() (producer, consumer string) {
+	producer, _ = d.producerStack.Load().(string)
+	consumer, _ = d.consumerStack.Load().(string)
+
+	return producer, consumer
+}
+
+// maxDiagFrames is the maximum number of frames recorded per call stack.
+const maxDiagFrames = 16
+
+// callerStack returns a short call stack of the queue operation which called checkRole, one
+// function and its location per frame.
+func callerStack() string {
+	var pcs [maxDiagFrames]uintptr
+	// Skip runtime.Callers, callerStack, checkRole and the checkProducer or checkConsumer wrapper.
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(4, pcs[:])])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%v\n\t%v:%v\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+
+	return b.String()
+}
+
+// goroutineID returns the ID of the calling goroutine, as reported in the header of its stack
+// trace.
+func goroutineID() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("spscqueue: failed to determine goroutine ID; %v", err))
+	}
+
+	return id
+}
+
+// markPreallocated records that the elements of the queue were pre-allocated using Fill.
+func (q *Queue[T]) markPreallocated() {
+	q.debug.preallocated = true
+}
+
+// poisonFront overwrites the `n` slots at the front of the queue, which the consumer is about to
+// free, so that stale reads of them stand out. Element types consisting only of numbers are filled
+// with the 0xDE byte pattern; other element types are zeroed, since no recognisable value can be
+// constructed for pointers safely. Zeroing is skipped for queues whose elements were pre-allocated
+// using Fill or WithLazyFill, since the Reserve-Commit pattern reuses them. Queues created using
+// WithReclaim are not poisoned at all, since the previous occupants of freed slots are reclaimed.
+func (q *Queue[T]) poisonFront(n uint64) {
+	p, ok := q.debug.poison.(*T)
+	if !ok {
+		p = new(T)
+		if q.reclaim != nil {
+			p = nil
+		} else if !numeric(reflect.TypeOf(p).Elem()) {
+			if q.debug.preallocated || q.lazyFill != nil {
+				p = nil
+			}
+		} else {
+			b := unsafe.Slice((*byte)(unsafe.Pointer(p)), unsafe.Sizeof(*p))
+			for i := range b {
+				b[i] = poisonByte
+			}
+		}
+		q.debug.poison = p
+	}
+	if p == nil {
+		return
+	}
+
+	for i := uint64(0); i < n; i++ {
+		q.items[q.slot(q.add(q.rIdx, i))] = *p
+	}
+}
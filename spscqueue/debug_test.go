@@ -0,0 +1,140 @@
+//go:build spscqueue_debug
+
+package spscqueue
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// runRecover runs `f` on a new goroutine and returns the value it panicked with, if any.
+func runRecover(f func()) any {
+	ret := make(chan any)
+	go func() {
+		defer func() {
+			ret <- recover()
+		}()
+		f()
+	}()
+
+	return <-ret
+}
+
+// Test that roles may be used from one goroutine each.
+func TestAffinity(t *testing.T) {
+	q := New[int](4)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			q.Push(i)
+		}
+		q.Close()
+	}()
+
+	for i := 0; i < 100; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+	if _, ok := q.Front(); ok {
+		t.Error("Got element from empty queue")
+	}
+	<-done
+}
+
+// Test that using a role from a second goroutine panics.
+func TestAffinityMigration(t *testing.T) {
+	q := New[int](4)
+	if r := runRecover(func() { q.Push(1) }); r != nil {
+		t.Fatalf("Unexpected panic; %v", r)
+	}
+	if r := runRecover(func() { q.Offer(2) }); r == nil {
+		t.Error("Producer migrating to another goroutine did not panic")
+	}
+
+	if r := runRecover(func() { q.Pop() }); r != nil {
+		t.Fatalf("Unexpected panic; %v", r)
+	}
+	if r := runRecover(func() { q.Front() }); r == nil {
+		t.Error("Consumer migrating to another goroutine did not panic")
+	}
+}
+
+// Test that the call stacks binding the roles are recorded and reported in panics.
+func TestDiagnostics(t *testing.T) {
+	q := New[int](4, WithDiagnostics())
+	if p, c := q.DiagInfo(); p != "" || c != "" {
+		t.Errorf("Got stacks before roles were bound; %q, %q", p, c)
+	}
+
+	if r := runRecover(func() { q.Push(1) }); r != nil {
+		t.Fatalf("Unexpected panic; %v", r)
+	}
+	if r := runRecover(func() { q.Pop() }); r != nil {
+		t.Fatalf("Unexpected panic; %v", r)
+	}
+	p, c := q.DiagInfo()
+	if !strings.Contains(p, "Push") || !strings.Contains(p, "TestDiagnostics") {
+		t.Errorf("Unexpected producer stack; %q", p)
+	}
+	if !strings.Contains(c, "Pop") || !strings.Contains(c, "TestDiagnostics") {
+		t.Errorf("Unexpected consumer stack; %q", c)
+	}
+
+	r := runRecover(func() { q.Push(2) })
+	if msg := fmt.Sprint(r); !strings.Contains(msg, p) || !strings.Contains(msg, c) {
+		t.Errorf("Panic does not report where the roles were bound; %v", msg)
+	}
+
+	if p, c := New[int](4).DiagInfo(); p != "" || c != "" {
+		t.Errorf("Got stacks without diagnostics; %q, %q", p, c)
+	}
+}
+
+// Test that slots freed by the consumer are poisoned.
+func TestPoison(t *testing.T) {
+	q := New[uint32](4)
+	q.Push(1)
+	q.Push(2)
+	q.Push(3)
+	freed := &q.items[q.slot(q.rIdx)]
+	if v := q.Pop(); v != 1 {
+		t.Errorf("Got incorrect value; %v != 1", v)
+	}
+	if *freed != 0xDEDEDEDE {
+		t.Errorf("Freed slot not poisoned; %#x", *freed)
+	}
+	span := q.ReadableSpan()
+	q.AdvanceN(uint64(len(span)))
+	for _, v := range span {
+		if v != 0xDEDEDEDE {
+			t.Errorf("Freed slot not poisoned; %#x", v)
+		}
+	}
+
+	// Element types containing pointers are zeroed instead.
+	type element struct {
+		n int
+		s *int
+	}
+	pq := New[element](4)
+	pq.Push(element{1, new(int)})
+	pfreed := &pq.items[pq.slot(pq.rIdx)]
+	pq.Pop()
+	if *pfreed != (element{}) {
+		t.Errorf("Freed slot not zeroed; %v", *pfreed)
+	}
+
+	// Pre-allocated elements must survive for reuse by Reserve.
+	rq := New[*int](4)
+	rq.Fill(func() *int { return new(int) })
+	v, _ := rq.Reserve()
+	rq.Commit()
+	rq.Pop()
+	if rq.items[0] != v {
+		t.Error("Pre-allocated element was overwritten")
+	}
+}
@@ -0,0 +1,27 @@
+package spscqueue
+
+// Deduper wraps a queue to suppress consecutive duplicate elements on the producer side.
+type Deduper[T comparable] struct {
+	q      *Queue[T]
+	last   T
+	pushed bool
+}
+
+// NewDeduper returns a Deduper which adds elements to `q`.
+func NewDeduper[T comparable](q *Queue[T]) *Deduper[T] {
+	return &Deduper[T]{q: q}
+}
+
+// Push adds the passed element to the queue unless it is equal to the element most recently added
+// through the Deduper. Push returns true if the element was added, and false if it was dropped as a
+// duplicate. Like Queue.Push, Push will block if the queue is full.
+// Push should be called by the producer.
+func (d *Deduper[T]) Push(el T) bool {
+	if d.pushed && el == d.last {
+		return false
+	}
+
+	d.q.Push(el)
+	d.last, d.pushed = el, true
+	return true
+}
@@ -0,0 +1,31 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for consecutive duplicate suppression.
+func TestDeduper(t *testing.T) {
+	q := New[int](16)
+	d := NewDeduper(q)
+
+	in := []int{0, 0, 1, 1, 1, 2, 1, 1, 3, 3}
+	want := []int{0, 1, 2, 1, 3}
+	var added int
+	for _, v := range in {
+		if d.Push(v) {
+			added++
+		}
+	}
+	if added != len(want) {
+		t.Errorf("Unexpected number of added elements; %v != %v", added, len(want))
+	}
+	if l := q.Len(); l != uint64(len(want)) {
+		t.Errorf("Unexpected length; %v != %v", l, len(want))
+	}
+	for _, w := range want {
+		if v := q.Pop(); v != w {
+			t.Errorf("Got incorrect value; %v != %v", v, w)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// MaxDrainAckAttempts is the number of times DrainAck passes the same element to `yield` without
+// it being acknowledged before DrainAck gives up on it.
+const MaxDrainAckAttempts = 16
+
+// DrainAck passes the elements in the queue to `yield` in order, along with an `ack` function which
+// removes the element from the queue. An element is only removed once `ack` is called, which must
+// happen before `yield` returns; calling `ack` more than once has no further effect. If `yield`
+// returns true without calling `ack`, the same element is passed to `yield` again, so that it can
+// be retried, up to MaxDrainAckAttempts times in a row; DrainAck then stops, so that an element
+// which is never acknowledged cannot keep it from returning. If `yield` returns false, DrainAck
+// stops as well. In either case an element which was not acknowledged remains at the front of the
+// queue. DrainAck does not block; it returns once the queue is empty. This provides at-least-once
+// processing: an element is never removed before the consumer has acknowledged it.
+// DrainAck should be called by the consumer.
+func (q *Queue[T]) DrainAck(yield func(T, func()) bool) {
+	var acked bool
+	ack := func() {
+		if !acked {
+			acked = true
+			q.Advance()
+		}
+	}
+
+	var attempts int
+	for el, ok := q.Front(); ok; el, ok = q.Front() {
+		acked = false
+		if !yield(el, ack) {
+			return
+		}
+		if acked {
+			attempts = 0
+		} else if attempts++; attempts == MaxDrainAckAttempts {
+			return
+		}
+	}
+}
+
+// DrainFilter removes the elements currently in the queue, passing those for which `keep` returns
+// true to `handle` and discarding the others, and returns the number of elements passed to
+// `handle`. Each element is removed before it is passed to `handle`. The producer's position is
+// only read once, on entry, so elements added while DrainFilter runs are left in the queue and a
+// fast producer cannot keep it from returning. DrainFilter does not block.
+// DrainFilter should be called by the consumer.
+func (q *Queue[T]) DrainFilter(keep func(T) bool, handle func(T)) int {
+	q.checkConsumer()
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	var handled int
+	for end := q.wIdxCached; q.rIdx != end; {
+		el := q.items[q.slot(q.rIdx)]
+		q.Advance()
+		if keep(el) {
+			handle(el)
+			handled++
+		}
+	}
+
+	return handled
+}
+
+// ProcessBudget removes up to `maxItems` elements from the queue and passes them to `handle` in
+// order, and returns the number of elements processed. Each element is removed before it is
+// passed to `handle`. ProcessBudget does not block; it returns early once the queue is empty, so a
+// consumer running inside a cooperative scheduler can yield between budgets.
+// ProcessBudget should be called by the consumer.
+func (q *Queue[T]) ProcessBudget(maxItems int, handle func(T)) int {
+	var n int
+	for ; n < maxItems; n++ {
+		el, ok := q.Front()
+		if !ok {
+			break
+		}
+		q.Advance()
+		handle(el)
+	}
+
+	return n
+}
+
+// TakeAll removes the elements currently in the queue and returns them in order in a newly
+// allocated slice, or nil if the queue is empty, e.g. to hand off the buffered work to another
+// subsystem at once. As with DrainFilter, the producer's position is only read once, on entry, so
+// elements added while TakeAll runs are left in the queue. TakeAll does not block.
+// TakeAll should be called by the consumer.
+func (q *Queue[T]) TakeAll() []T {
+	q.checkConsumer()
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	n := q.length(q.rIdx, q.wIdxCached)
+	if n == 0 {
+		return nil
+	}
+
+	els := make([]T, n)
+	q.peek(els, 0)
+	q.AdvanceN(n)
+
+	return els
+}
@@ -0,0 +1,176 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test that elements are only removed once acknowledged.
+func TestDrainAck(t *testing.T) {
+	q := New[int](8)
+	for i := 0; i < 6; i++ {
+		q.Push(i)
+	}
+
+	// Even elements are only acknowledged on their second attempt, and iteration stops at 3.
+	var got []int
+	attempts := map[int]int{}
+	q.DrainAck(func(v int, ack func()) bool {
+		got = append(got, v)
+		attempts[v]++
+		if v == 3 {
+			return false
+		}
+		if v%2 == 1 || attempts[v] == 2 {
+			ack()
+			ack()
+		}
+		return true
+	})
+	want := []int{0, 0, 1, 2, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("Unexpected number of yielded elements; %v != %v", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Got incorrect value; %v != %v", got[i], want[i])
+		}
+	}
+
+	// The unacknowledged element remains at the front of the queue.
+	if l := q.Len(); l != 3 {
+		t.Errorf("Unexpected length; %v != 3", l)
+	}
+	got = got[:0]
+	q.DrainAck(func(v int, ack func()) bool {
+		got = append(got, v)
+		ack()
+		return true
+	})
+	for i, v := range got {
+		if v != 3+i {
+			t.Errorf("Got incorrect value; %v != %v", v, 3+i)
+		}
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test that DrainAck gives up on an element which is never acknowledged.
+func TestDrainAckUnacknowledged(t *testing.T) {
+	q := New[int](8)
+	q.Push(0)
+	q.Push(1)
+
+	var attempts int
+	q.DrainAck(func(v int, ack func()) bool {
+		if v != 0 {
+			t.Errorf("Got incorrect value; %v != 0", v)
+		}
+		attempts++
+		return true
+	})
+	if attempts != MaxDrainAckAttempts {
+		t.Errorf("Unexpected number of attempts; %v != %v", attempts, MaxDrainAckAttempts)
+	}
+	if l := q.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+}
+
+// Test that DrainFilter only handles matching elements, in order, and discards the rest.
+func TestDrainFilter(t *testing.T) {
+	q := New[int](8)
+	for i := 0; i < 7; i++ {
+		q.Push(i)
+	}
+
+	var got []int
+	n := q.DrainFilter(func(v int) bool { return v%3 == 0 }, func(v int) {
+		got = append(got, v)
+		// Elements added while draining are left in the queue.
+		q.Push(v + 100)
+	})
+	want := []int{0, 3, 6}
+	if n != len(want) || len(got) != len(want) {
+		t.Fatalf("Unexpected number of handled elements; %v, %v != %v", n, len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Got incorrect value; %v != %v", got[i], want[i])
+		}
+	}
+	if l := q.Len(); l != uint64(len(want)) {
+		t.Errorf("Unexpected length; %v != %v", l, len(want))
+	}
+	for _, v := range want {
+		if p := q.Pop(); p != v+100 {
+			t.Errorf("Got incorrect value; %v != %v", p, v+100)
+		}
+	}
+
+	if n := q.DrainFilter(func(int) bool { return true }, func(int) {}); n != 0 {
+		t.Errorf("Unexpected number of handled elements; %v != 0", n)
+	}
+}
+
+// Test that ProcessBudget handles at most the budget, in order.
+func TestProcessBudget(t *testing.T) {
+	q := New[int](8)
+	for i := 0; i < 7; i++ {
+		q.Push(i)
+	}
+
+	var next int
+	handle := func(v int) {
+		if v != next {
+			t.Errorf("Got incorrect value; %v != %v", v, next)
+		}
+		next++
+	}
+	for _, want := range []int{3, 3, 1, 0} {
+		if n := q.ProcessBudget(3, handle); n != want {
+			t.Errorf("Unexpected number of processed elements; %v != %v", n, want)
+		}
+	}
+	if n := q.ProcessBudget(0, handle); n != 0 {
+		t.Errorf("Unexpected number of processed elements; %v != 0", n)
+	}
+	if next != 7 {
+		t.Errorf("Unexpected number of handled elements; %v != 7", next)
+	}
+}
+
+// Test for taking all wrapped elements out of the queue at once.
+func TestTakeAll(t *testing.T) {
+	q := New[int](8)
+	if els := q.TakeAll(); els != nil {
+		t.Errorf("Got elements from empty queue; %v", els)
+	}
+
+	// Move the indices so that the elements wrap around the end of the buffer.
+	for i := 0; i < 6; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(6)
+
+	const numItems = 7
+	for i := 0; i < numItems; i++ {
+		q.Push(i)
+	}
+	els := q.TakeAll()
+	if len(els) != numItems {
+		t.Fatalf("Unexpected length; %v != %v", len(els), numItems)
+	}
+	for i, v := range els {
+		if v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+	if els = q.TakeAll(); els != nil {
+		t.Errorf("Got elements from empty queue; %v", els)
+	}
+}
@@ -0,0 +1,26 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// PushOrDrop adds the passed element to the queue if there is an available slot and the queue has
+// not been closed, and drops it otherwise. PushOrDrop returns true if the element was added and
+// false if it was dropped. It is equivalent to Offer; the elements dropped by either are counted
+// by DroppedCount, so that a producer which tolerates loss can report it.
+// PushOrDrop should be called by the producer.
+func (q *Queue[T]) PushOrDrop(el T) bool {
+	return q.Offer(el)
+}
+
+// recordDrop counts an element which the producer failed to add.
+func (q *Queue[T]) recordDrop() {
+	atomic.StoreUint64(&q.dropped, q.dropped+1)
+}
+
+// DroppedCount returns the number of elements which PushOrDrop, Offer, Put, OfferE and OfferSpin
+// failed to add to the queue, because it was full or closed.
+// Any thread may call DroppedCount.
+func (q *Queue[T]) DroppedCount() uint64 {
+	return atomic.LoadUint64(&q.dropped)
+}
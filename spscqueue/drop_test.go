@@ -0,0 +1,33 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for counting the elements dropped by an overflowing queue.
+func TestDroppedCount(t *testing.T) {
+	q := New[int](4)
+	for i := 0; i < 10; i++ {
+		if ok := q.PushOrDrop(i); ok != (i < 4) {
+			t.Errorf("Unexpected result of PushOrDrop(%v); %v", i, ok)
+		}
+	}
+	if n := q.DroppedCount(); n != 6 {
+		t.Errorf("Unexpected dropped count; %v != 6", n)
+	}
+
+	// Dropped elements must not have displaced the enqueued ones.
+	for i := 0; i < 4; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+
+	q.Offer(1)
+	q.Close()
+	q.Offer(2)
+	q.OfferE(3)
+	if n := q.DroppedCount(); n != 8 {
+		t.Errorf("Unexpected dropped count; %v != 8", n)
+	}
+}
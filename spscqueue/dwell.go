@@ -0,0 +1,62 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithDwellTracking makes the queue measure the time each element dwells in it, from being
+// published by the producer until being removed by the consumer, as reported by DwellStats. The
+// time at which each element was published is kept in a separate slice parallel to the underlying
+// buffer, so the element type is unaffected; the slice is accessed like the buffer itself, and not
+// on the cache lines of the indices. Tracking reads the clock on every publication and removal,
+// and disables the fast paths of Offer and Advance.
+func WithDwellTracking() Option {
+	return func(o *options) {
+		o.dwell = true
+	}
+}
+
+// dwellClock returns the current time as used for dwell tracking, i.e. the monotonic time elapsed
+// since the creation of the queue, in nanoseconds.
+func (q *Queue[T]) dwellClock() int64 {
+	return int64(time.Since(q.dwellBase))
+}
+
+// stampDwell records the current time as the publication time of the slots from index `from` up to
+// index `to`. It is called before the slots are published, so the consumer observes the stamps
+// along with the elements.
+// stampDwell should be called by the producer.
+func (q *Queue[T]) stampDwell(from, to uint64) {
+	now := q.dwellClock()
+	for idx := from; idx != to; idx = q.next(idx) {
+		q.dwell[q.slot(idx)] = now
+	}
+}
+
+// recordDwell updates the dwell statistics from the `n` elements at the front of the queue, which
+// are about to be removed.
+// recordDwell should be called by the consumer.
+func (q *Queue[T]) recordDwell(n uint64) {
+	now := q.dwellClock()
+	max := q.dwellMax
+	var last int64
+	for i := uint64(0); i < n; i++ {
+		if last = now - q.dwell[q.slot(q.add(q.rIdx, i))]; last > max {
+			max = last
+		}
+	}
+	atomic.StoreInt64(&q.dwellLast, last)
+	if max != q.dwellMax {
+		atomic.StoreInt64(&q.dwellMax, max)
+	}
+}
+
+// DwellStats returns the time the most recently removed element dwelled in the queue, and the
+// longest time any element has dwelled in it. Elements removed in a batch, e.g. by AdvanceN, are
+// all accounted for in the maximum, and the last of them determines the most recent dwell time.
+// DwellStats returns 0 durations unless the queue was created using WithDwellTracking.
+// Any thread may call DwellStats.
+func (q *Queue[T]) DwellStats() (last, max time.Duration) {
+	return time.Duration(atomic.LoadInt64(&q.dwellLast)), time.Duration(atomic.LoadInt64(&q.dwellMax))
+}
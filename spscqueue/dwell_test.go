@@ -0,0 +1,47 @@
+package spscqueue
+
+import (
+	"testing"
+	"time"
+)
+
+// Test that the reported dwell times cover the delays between pushing and popping.
+func TestDwellStats(t *testing.T) {
+	if last, max := New[int](4).DwellStats(); last != 0 || max != 0 {
+		t.Errorf("Got dwell times without tracking; %v, %v", last, max)
+	}
+
+	const delay = 10 * time.Millisecond
+	q := New[int](4, WithDwellTracking())
+	start := time.Now()
+	q.Push(1)
+	time.Sleep(delay)
+	q.Push(2)
+	q.Pop()
+	elapsed := time.Since(start)
+	last, max := q.DwellStats()
+	if last < delay || last > elapsed {
+		t.Errorf("Implausible dwell time; %v not in [%v, %v]", last, delay, elapsed)
+	}
+	if max != last {
+		t.Errorf("Got incorrect maximum; %v != %v", max, last)
+	}
+
+	// The second element has dwelled for a shorter time, which does not lower the maximum.
+	q.Pop()
+	if last, max = q.DwellStats(); last >= max {
+		t.Errorf("Got incorrect dwell times; %v >= %v", last, max)
+	}
+
+	// The dwell times survive resizing, and batches update the maximum.
+	copy(q.ReserveN(3), []int{3, 4, 5})
+	q.CommitN(3)
+	if err := q.Resize(8); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * delay)
+	q.AdvanceN(3)
+	if last, max = q.DwellStats(); last < 2*delay || max != last {
+		t.Errorf("Got incorrect dwell times; %v, %v", last, max)
+	}
+}
@@ -0,0 +1,17 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// notify signals the eventfd of the queue if the producer just published elements into an empty
+// queue, i.e. the consumer had already caught up with the previous back of the queue `wIdxPrev`.
+// Since the write index is stored before the read index is loaded, either the consumer observes
+// the new elements before it waits on the eventfd, or the producer observes that the consumer has
+// drained the queue and signals it.
+// notify should be called by the producer.
+func (q *Queue[T]) notify(wIdxPrev uint64) {
+	if atomic.LoadUint64(&q.rIdx) == wIdxPrev {
+		q.event.signal()
+	}
+}
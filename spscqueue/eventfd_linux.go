@@ -0,0 +1,55 @@
+package spscqueue
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// eventNotifier wakes a consumer waiting on an eventfd.
+type eventNotifier struct {
+	fd int
+}
+
+// newEventNotifier creates a non-blocking eventfd, panicking if the kernel refuses to create one.
+func newEventNotifier() *eventNotifier {
+	fd, err := unix.Eventfd(0, unix.EFD_NONBLOCK|unix.EFD_CLOEXEC)
+	if err != nil {
+		panic(fmt.Sprintf("spscqueue: creating eventfd: %v", err))
+	}
+
+	return &eventNotifier{fd: fd}
+}
+
+// signal increments the eventfd counter, making the eventfd readable. A failed write means that
+// the counter is about to overflow, in which case the eventfd is readable anyway.
+func (e *eventNotifier) signal() {
+	one := uint64(1)
+	_, _ = unix.Write(e.fd, (*[8]byte)(unsafe.Pointer(&one))[:])
+}
+
+// WithEventfd makes the queue signal a Linux eventfd whenever the producer adds elements to an
+// empty queue, and when the queue is closed. The eventfd is available through EventFD, so that a
+// consumer can wait for elements using epoll or poll instead of spinning or sleeping. The
+// eventfd is only signalled on the transition from empty to non-empty, which suits edge-triggered
+// epoll; to avoid missing a wakeup, the consumer must read the eventfd to reset it before
+// processing the queue until it is empty, and only wait on the eventfd once it has found the queue
+// empty. WithEventfd is only available on Linux.
+func WithEventfd() Option {
+	return func(o *options) {
+		o.eventfd = true
+	}
+}
+
+// EventFD returns the eventfd of a queue created using WithEventfd, or -1 if the queue has none.
+// The eventfd is non-blocking and remains open for the lifetime of the queue; the caller may close
+// it once the producer has stopped using the queue.
+// Any thread may call EventFD.
+func (q *Queue[T]) EventFD() int {
+	if q.event == nil {
+		return -1
+	}
+
+	return q.event.fd
+}
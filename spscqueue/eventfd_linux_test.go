@@ -0,0 +1,66 @@
+package spscqueue
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// eventFDReadable reports whether the eventfd becomes readable within `timeout` milliseconds, and
+// resets it.
+func eventFDReadable(t *testing.T, fd int, timeout int) bool {
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	n, err := unix.Poll(fds, timeout)
+	if err != nil {
+		t.Fatalf("Failed to poll eventfd; %v", err)
+	}
+	if n == 0 {
+		return false
+	}
+	var buf [8]byte
+	if _, err := unix.Read(fd, buf[:]); err != nil {
+		t.Fatalf("Failed to read eventfd; %v", err)
+	}
+	return true
+}
+
+// Test for signalling an eventfd when elements are added to an empty queue.
+func TestEventFD(t *testing.T) {
+	if fd := New[int](4).EventFD(); fd != -1 {
+		t.Errorf("Got eventfd for queue without one; %v", fd)
+	}
+
+	q := New[int](4, WithEventfd())
+	fd := q.EventFD()
+	defer unix.Close(fd)
+	if fd < 0 {
+		t.Fatalf("Got invalid eventfd; %v", fd)
+	}
+	if eventFDReadable(t, fd, 0) {
+		t.Error("Eventfd readable before any push")
+	}
+
+	q.Push(1)
+	if !eventFDReadable(t, fd, 1000) {
+		t.Error("Eventfd not readable after push into empty queue")
+	}
+
+	// Pushing into a non-empty queue must not signal the eventfd again.
+	q.Push(2)
+	if eventFDReadable(t, fd, 0) {
+		t.Error("Eventfd readable after push into non-empty queue")
+	}
+
+	q.Pop()
+	q.Pop()
+	copy(q.ReserveN(2), []int{3, 4})
+	q.CommitN(2)
+	if !eventFDReadable(t, fd, 1000) {
+		t.Error("Eventfd not readable after commit into empty queue")
+	}
+
+	q.Close()
+	if !eventFDReadable(t, fd, 1000) {
+		t.Error("Eventfd not readable after close")
+	}
+}
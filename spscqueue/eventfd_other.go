@@ -0,0 +1,12 @@
+//go:build !linux
+
+package spscqueue
+
+// eventNotifier is empty, since WithEventfd is only available on Linux.
+type eventNotifier struct{}
+
+func newEventNotifier() *eventNotifier {
+	panic("spscqueue: eventfd is only available on Linux")
+}
+
+func (e *eventNotifier) signal() {}
@@ -0,0 +1,26 @@
+package spscqueue
+
+import (
+	"time"
+)
+
+// PopExpired removes the elements at the front of the queue whose timestamp, as returned by `tsOf`,
+// is not after `now`, and passes them to `handle` in order. It stops at the first element which has
+// not yet expired, or once the queue is empty, and returns the number of elements handled.
+// PopExpired does not block. The producer must push elements in non-decreasing timestamp order;
+// an expired element queued behind one which has not expired is not removed.
+// PopExpired should be called by the consumer.
+func PopExpired[T any](q *Queue[T], now time.Time, tsOf func(T) time.Time, handle func(T)) int {
+	var n int
+	var el T
+	expired := func(v T) bool {
+		el = v
+		return !tsOf(v).After(now)
+	}
+	for q.PeekFunc(expired) {
+		handle(el)
+		n++
+	}
+
+	return n
+}
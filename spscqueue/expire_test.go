@@ -0,0 +1,42 @@
+package spscqueue
+
+import (
+	"testing"
+	"time"
+)
+
+// Test for removing expired elements from a mix of expired and future-dated elements.
+func TestPopExpired(t *testing.T) {
+	q := New[time.Time](8)
+	now := time.Now()
+	offsets := []time.Duration{-3 * time.Second, -time.Second, 0, time.Second, 2 * time.Second}
+	for _, d := range offsets {
+		q.Push(now.Add(d))
+	}
+
+	var handled []time.Time
+	handle := func(ts time.Time) { handled = append(handled, ts) }
+	tsOf := func(ts time.Time) time.Time { return ts }
+
+	if n := PopExpired(q, now, tsOf, handle); n != 3 {
+		t.Errorf("Unexpected number of expired elements; %v != 3", n)
+	}
+	for i, ts := range handled {
+		if !ts.Equal(now.Add(offsets[i])) {
+			t.Errorf("Got incorrect value; %v != %v", ts, now.Add(offsets[i]))
+		}
+	}
+	if l := q.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+	if n := PopExpired(q, now, tsOf, handle); n != 0 {
+		t.Errorf("Removed elements which have not expired; %v", n)
+	}
+
+	if n := PopExpired(q, now.Add(time.Minute), tsOf, handle); n != 2 {
+		t.Errorf("Unexpected number of expired elements; %v != 2", n)
+	}
+	if n := PopExpired(q, now.Add(time.Minute), tsOf, handle); n != 0 {
+		t.Errorf("Removed elements from empty queue; %v", n)
+	}
+}
@@ -0,0 +1,43 @@
+package spscqueue
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+)
+
+// expvarMu serialises PublishExpvar, so that concurrent calls cannot publish the same name twice.
+var expvarMu sync.Mutex
+
+// PublishExpvar publishes the length, capacity, high-water mark and dropped count of the queue as
+// the expvar variables `prefix`.len, `prefix`.cap, `prefix`.high_water and `prefix`.dropped. The
+// variables read the current values each time they are inspected. Since expvar variables cannot be
+// unregistered, PublishExpvar returns an error rather than publishing anything if any of the names
+// is already in use. This check is atomic with respect to other calls to PublishExpvar, but not to
+// names published through expvar directly. The high-water mark is 0 unless the queue was created
+// using WithStats.
+// Any thread may call PublishExpvar.
+func (q *Queue[T]) PublishExpvar(prefix string) error {
+	vars := []struct {
+		name string
+		f    func() any
+	}{
+		{"len", func() any { return q.Len() }},
+		{"cap", func() any { return q.Cap() }},
+		{"high_water", func() any { return q.HighWaterMark() }},
+		{"dropped", func() any { return q.DroppedCount() }},
+	}
+
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	for _, v := range vars {
+		if name := prefix + "." + v.name; expvar.Get(name) != nil {
+			return fmt.Errorf("spscqueue: expvar %q is already published", name)
+		}
+	}
+	for _, v := range vars {
+		expvar.Publish(prefix+"."+v.name, expvar.Func(v.f))
+	}
+
+	return nil
+}
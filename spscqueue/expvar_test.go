@@ -0,0 +1,46 @@
+package spscqueue
+
+import (
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+// expvarRun numbers the runs of the expvar tests, since published names cannot be reused.
+var expvarRun int32
+
+// Test for publishing queue statistics through expvar.
+func TestPublishExpvar(t *testing.T) {
+	prefix := fmt.Sprintf("%v.%v", t.Name(), atomic.AddInt32(&expvarRun, 1))
+	q := New[int](4, WithStats())
+	if err := q.PublishExpvar(prefix); err != nil {
+		t.Fatalf("Failed to publish expvars; %v", err)
+	}
+
+	for i := 0; i < 6; i++ {
+		q.Offer(i)
+	}
+	q.Pop()
+
+	for name, want := range map[string]string{
+		"len":        "3",
+		"cap":        "4",
+		"high_water": "4",
+		"dropped":    "2",
+	} {
+		name = prefix + "." + name
+		v := expvar.Get(name)
+		if v == nil {
+			t.Errorf("Expvar %v not published", name)
+			continue
+		}
+		if got := v.String(); got != want {
+			t.Errorf("Got incorrect value for %v; %v != %v", name, got, want)
+		}
+	}
+
+	if err := New[int](4).PublishExpvar(prefix); err == nil {
+		t.Error("Published expvars under a name which is already in use")
+	}
+}
@@ -0,0 +1,78 @@
+package spscqueue
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+)
+
+// frameHeaderLen is the length of the big-endian length prefix of a frame.
+const frameHeaderLen = 4
+
+// ReadFrame reads a length-prefixed frame from a byte queue. A frame consists of the length of its
+// payload as a 4-byte big-endian integer, followed by the payload. If a complete frame is available
+// at the front of the queue, ReadFrame copies its payload into `buf`, removes the frame from the
+// queue and returns the payload length and true. ReadFrame does not block; if the frame is not yet
+// complete, it returns 0 and false. If the payload does not fit into `buf`, the frame is left in the
+// queue and ReadFrame returns the payload length and false, so that the caller may retry with a
+// larger buffer. Frames which exceed the capacity of the queue can never be read.
+// ReadFrame should be called by the consumer.
+func ReadFrame(q *Queue[byte], buf []byte) (int, bool) {
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+
+	var hdr [frameHeaderLen]byte
+	if q.peek(hdr[:], 0) < frameHeaderLen {
+		return 0, false
+	}
+	n := uint64(binary.BigEndian.Uint32(hdr[:]))
+	if q.length(q.rIdx, q.wIdxCached) < frameHeaderLen+n {
+		return 0, false
+	}
+	if uint64(len(buf)) < n {
+		return int(n), false
+	}
+
+	q.peek(buf[:n], frameHeaderLen)
+	q.AdvanceN(frameHeaderLen + n)
+
+	return int(n), true
+}
+
+// PutUvarint adds `x` to a byte queue as a varint, encoded like binary.PutUvarint, and returns the
+// number of bytes added. The encoded bytes are published to the consumer at once, so the consumer
+// never observes a partial varint. PutUvarint does not block; if the queue does not have room for
+// the whole varint or has been closed, nothing is added and PutUvarint returns 0.
+// PutUvarint should be called by the producer.
+func PutUvarint(q *Queue[byte], x uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	if q.Cap()-q.length(q.rIdxCached, q.wIdx) < uint64(n) || q.PushSlice(buf[:n]) != nil {
+		return 0
+	}
+
+	return n
+}
+
+// ReadUvarint reads a varint, as added by PutUvarint, from the front of a byte queue and returns
+// its value and the number of bytes it occupied, which are removed from the queue. ReadUvarint
+// does not block; if the varint is not yet complete, the queue is left unchanged and ReadUvarint
+// returns 0 and 0. If the varint overflows a uint64, the queue is left unchanged as well and
+// ReadUvarint returns 0 and the negated number of bytes read, like binary.Uvarint.
+// ReadUvarint should be called by the consumer.
+func ReadUvarint(q *Queue[byte]) (uint64, int) {
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+
+	var buf [binary.MaxVarintLen64]byte
+	m := q.peek(buf[:], 0)
+	x, n := binary.Uvarint(buf[:m])
+	if n == 0 && m == binary.MaxVarintLen64 {
+		// No varint continues beyond the maximum length.
+		return 0, -binary.MaxVarintLen64
+	} else if n <= 0 {
+		return 0, n
+	}
+	q.AdvanceN(uint64(n))
+
+	return x, n
+}
@@ -0,0 +1,109 @@
+package spscqueue
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// pushFrame adds a length-prefixed frame to the queue.
+func pushFrame(q *Queue[byte], payload string) {
+	var hdr [frameHeaderLen]byte
+	binary.BigEndian.PutUint32(hdr[:], uint32(len(payload)))
+	for _, c := range append(hdr[:], payload...) {
+		q.Push(c)
+	}
+}
+
+// Test for reading length-prefixed frames, including frames which wrap around.
+func TestReadFrame(t *testing.T) {
+	q := New[byte](16)
+	buf := make([]byte, 16)
+
+	if n, ok := ReadFrame(q, buf); ok || n != 0 {
+		t.Errorf("Read frame from empty queue; %v", n)
+	}
+
+	// Move through the underlying buffer such that the payload of the second frame and the header
+	// of the fourth frame straddle its end.
+	for _, payload := range []string{"first", "second!", "abcdefgh", "wrap"} {
+		pushFrame(q, payload)
+		if n, ok := ReadFrame(q, buf[:3]); ok || n != len(payload) {
+			t.Errorf("Read frame into short buffer; %v", n)
+		}
+		if n, ok := ReadFrame(q, buf); !ok || string(buf[:n]) != payload {
+			t.Errorf("Got incorrect frame; %q != %q", buf[:n], payload)
+		}
+	}
+
+	// An incomplete frame must be left in the queue.
+	for _, c := range []byte{0, 0, 0, 3, 'a', 'b'} {
+		q.Push(c)
+	}
+	if n, ok := ReadFrame(q, buf); ok || n != 0 {
+		t.Errorf("Read incomplete frame; %v", n)
+	}
+	q.Push('c')
+	if n, ok := ReadFrame(q, buf); !ok || string(buf[:n]) != "abc" {
+		t.Errorf("Got incorrect frame; %q != %q", buf[:n], "abc")
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test for round-tripping varints, including varints which wrap around.
+func TestUvarint(t *testing.T) {
+	q := New[byte](12)
+	if x, n := ReadUvarint(q); x != 0 || n != 0 {
+		t.Errorf("Read varint from empty queue; %v, %v", x, n)
+	}
+
+	for round := 0; round < 3; round++ {
+		for _, x := range []uint64{0, 1, 127, 128, 300, 1 << 35, ^uint64(0)} {
+			want := binary.PutUvarint(make([]byte, binary.MaxVarintLen64), x)
+			if n := PutUvarint(q, x); n != want {
+				t.Errorf("Unexpected varint length; %v != %v", n, want)
+			}
+			if got, n := ReadUvarint(q); got != x || n != want {
+				t.Errorf("Got incorrect value; %v, %v != %v, %v", got, n, x, want)
+			}
+		}
+	}
+
+	// A varint which does not fit must not be added at all.
+	for i := 0; i < 3; i++ {
+		q.Push(0)
+	}
+	if n := PutUvarint(q, ^uint64(0)); n != 0 {
+		t.Errorf("Added varint to a queue without room for it; %v", n)
+	}
+	if l := q.Len(); l != 3 {
+		t.Errorf("Unexpected length; %v != 3", l)
+	}
+	q.AdvanceN(3)
+
+	// An incomplete varint must be left in the queue.
+	q.Push(0x80)
+	if x, n := ReadUvarint(q); x != 0 || n != 0 {
+		t.Errorf("Read incomplete varint; %v, %v", x, n)
+	}
+	q.Push(0x01)
+	if x, n := ReadUvarint(q); x != 128 || n != 2 {
+		t.Errorf("Got incorrect value; %v, %v != 128, 2", x, n)
+	}
+
+	// So must varints which overflow, either in their final byte or by not ending in time.
+	for _, last := range []byte{0x7f, 0xff} {
+		for i := 0; i < binary.MaxVarintLen64-1; i++ {
+			q.Push(0xff)
+		}
+		q.Push(last)
+		if x, n := ReadUvarint(q); x != 0 || n != -binary.MaxVarintLen64 {
+			t.Errorf("Read overflowing varint; %v, %v", x, n)
+		}
+		if l := q.Len(); l != binary.MaxVarintLen64 {
+			t.Errorf("Unexpected length; %v != %v", l, binary.MaxVarintLen64)
+		}
+		q.AdvanceN(binary.MaxVarintLen64)
+	}
+}
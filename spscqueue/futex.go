@@ -0,0 +1,71 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// WithFutex makes a blocked Push or Pop, and the operations building on them such as ReserveWait
+// and FrontBlocking, park the goroutine rather than waiting using the wait strategy, such that it
+// consumes no processor time until the other side makes progress. On Linux the goroutine parks on
+// a futex, which the other side wakes as soon as it adds an element to an empty queue or frees a
+// slot of a full one; on other platforms it parks on a condition variable instead. The other side
+// only enters the kernel if a goroutine is actually parked, but checks for one each time it
+// publishes or frees slots, so WithFutex disables the fast paths of Offer and Advance. Other
+// blocking operations, e.g. WaitForSpace and Flush, keep using the wait strategy.
+func WithFutex() Option {
+	return func(o *options) {
+		o.futex = true
+	}
+}
+
+// futexPair holds the parkers of the consumer waiting for elements and of the producer waiting for
+// free slots. Each parker is written by the side which parks on it and read by the other side, so
+// they are kept on separate cache lines.
+type futexPair struct {
+	notEmpty parker
+	_        cacheLinePad
+	notFull  parker
+}
+
+// newFutexPair returns the parkers for a queue created using WithFutex.
+func newFutexPair() *futexPair {
+	f := &futexPair{}
+	f.notEmpty.init()
+	f.notFull.init()
+
+	return f
+}
+
+// parker parks one side of the queue until the other side wakes it. `gen` is incremented by each
+// wakeup, and is the futex word on Linux; `waiting` is set while the parking side prepares to park
+// or is parked, so that the other side only wakes it if necessary.
+type parker struct {
+	gen     uint32
+	waiting uint32
+	sys     parkerSys
+}
+
+// await parks the calling goroutine until `ready` reports true. A wakeup cannot be lost between
+// checking `ready` and parking: `waiting` is stored before `ready` loads the other side's index,
+// and the other side stores its index before loading `waiting`, so either `ready` observes the
+// progress or wake observes the parking side and increments `gen`. In the latter case, park
+// returns at once if `gen` has already moved on from the value loaded before checking `ready`.
+func (p *parker) await(ready func() bool) {
+	for {
+		gen := atomic.LoadUint32(&p.gen)
+		atomic.StoreUint32(&p.waiting, 1)
+		if ready() {
+			break
+		}
+		p.park(gen)
+	}
+	atomic.StoreUint32(&p.waiting, 0)
+}
+
+// wake wakes the other side if it is parked on `p`, or about to park. wake is called after storing
+// the index which lets the other side make progress.
+func (p *parker) wake() {
+	if atomic.LoadUint32(&p.waiting) != 0 {
+		p.signal()
+	}
+}
@@ -0,0 +1,35 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// The futex operations, which are private to the process.
+const (
+	futexWaitPrivate = 0 | 128
+	futexWakePrivate = 1 | 128
+)
+
+// parkerSys is empty, since parkers use the futex word `gen` on Linux.
+type parkerSys struct{}
+
+func (p *parker) init() {}
+
+// park sleeps on the futex word until it is woken, unless the word no longer holds `gen`. The
+// kernel compares the word under the futex lock, so a wakeup which increments the word after it was
+// loaded cannot be missed. Spurious returns, e.g. due to signals, are handled by the caller
+// checking its condition again.
+func (p *parker) park(gen uint32) {
+	_, _, _ = unix.Syscall6(unix.SYS_FUTEX, uintptr(unsafe.Pointer(&p.gen)), futexWaitPrivate,
+		uintptr(gen), 0, 0, 0)
+}
+
+// signal increments the futex word and wakes the goroutine sleeping on it, if any.
+func (p *parker) signal() {
+	atomic.AddUint32(&p.gen, 1)
+	_, _, _ = unix.Syscall6(unix.SYS_FUTEX, uintptr(unsafe.Pointer(&p.gen)), futexWakePrivate, 1,
+		0, 0, 0)
+}
@@ -0,0 +1,47 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// cpuTime returns the processor time consumed by the process so far.
+func cpuTime(t *testing.T) time.Duration {
+	var ru unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_SELF, &ru); err != nil {
+		t.Fatalf("Failed to get resource usage; %v", err)
+	}
+
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+}
+
+// Test that a consumer parked on a futex consumes no processor time and wakes promptly.
+func TestFutexParked(t *testing.T) {
+	q := New[time.Time](4, WithFutex())
+	woken := make(chan time.Duration)
+	go func() {
+		pushed := q.Pop()
+		woken <- time.Since(pushed)
+	}()
+
+	for atomic.LoadUint32(&q.futex.notEmpty.waiting) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Give the consumer time to enter the kernel.
+	time.Sleep(10 * time.Millisecond)
+
+	const parked = 100 * time.Millisecond
+	start := cpuTime(t)
+	time.Sleep(parked)
+	if used := cpuTime(t) - start; used > parked/4 {
+		t.Errorf("Consumed too much processor time while parked; %v > %v", used, parked/4)
+	}
+
+	q.Push(time.Now())
+	if d := <-woken; d > 50*time.Millisecond {
+		t.Errorf("Consumer woke too late; %v", d)
+	}
+}
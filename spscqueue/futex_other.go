@@ -0,0 +1,37 @@
+//go:build !linux
+
+package spscqueue
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// parkerSys holds the condition variable on which parkers park, since futexes are only available
+// on Linux.
+type parkerSys struct {
+	mu   sync.Mutex
+	cond sync.Cond
+}
+
+func (p *parker) init() {
+	p.sys.cond.L = &p.sys.mu
+}
+
+// park waits on the condition variable until `gen` has been incremented. Since signal increments
+// `gen` while holding the mutex, the increment cannot fall between the check and the wait.
+func (p *parker) park(gen uint32) {
+	p.sys.mu.Lock()
+	for atomic.LoadUint32(&p.gen) == gen {
+		p.sys.cond.Wait()
+	}
+	p.sys.mu.Unlock()
+}
+
+// signal increments `gen` and wakes the goroutine waiting on the condition variable, if any.
+func (p *parker) signal() {
+	p.sys.mu.Lock()
+	atomic.AddUint32(&p.gen, 1)
+	p.sys.mu.Unlock()
+	p.sys.cond.Signal()
+}
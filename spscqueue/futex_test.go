@@ -0,0 +1,47 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// SPSC test for parking both sides of a small queue.
+func TestFutex(t *testing.T) {
+	const numItems = 20000
+	for _, size := range []uint{1, 3} {
+		q := New[int](size, WithFutex())
+		wg := sync.WaitGroup{}
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			for i := 0; i < numItems; i++ {
+				if i%2 == 0 {
+					q.Push(i)
+				} else {
+					*q.ReserveWait() = i
+					q.Commit()
+				}
+			}
+		}(&wg)
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			for i := 0; i < numItems; i++ {
+				var v int
+				if i%3 == 0 {
+					v = q.FrontBlocking()
+					q.AdvanceN(1)
+				} else {
+					v = q.Pop()
+				}
+				if v != i {
+					t.Errorf("Got incorrect value; %v != %v", v, i)
+				}
+			}
+		}(&wg)
+
+		wg.Wait()
+	}
+}
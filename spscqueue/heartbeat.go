@@ -0,0 +1,33 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// PopWithHeartbeat is a variant of Pop which calls `beat` each time it has been waiting for an
+// element for another `interval`, e.g. to keep a watchdog fed while the queue is legitimately idle.
+// PopWithHeartbeat keeps waiting after calling `beat`, and only returns once an element is
+// available, which it removes and returns like Pop. `beat` runs on the consumer's goroutine.
+// PopWithHeartbeat should be called by the consumer.
+func (q *Queue[T]) PopWithHeartbeat(interval time.Duration, beat func()) T {
+	q.checkConsumer()
+	if q.rIdx == q.wIdxCached {
+		q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	}
+	if q.rIdx == q.wIdxCached {
+		var spins uint64
+		deadline := time.Now().Add(interval)
+		for q.rIdx == q.wIdxCached {
+			if now := time.Now(); !now.Before(deadline) {
+				beat()
+				deadline = now.Add(interval)
+			}
+			q.waitStrategy().Wait(spins)
+			spins++
+			q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+		}
+	}
+
+	return q.Pop()
+}
@@ -0,0 +1,34 @@
+package spscqueue
+
+import (
+	"testing"
+	"time"
+)
+
+// Test that the heartbeat fires during an idle stretch before an element arrives.
+func TestPopWithHeartbeat(t *testing.T) {
+	q := New[int](4)
+	var beats int
+
+	q.Push(1)
+	if v := q.PopWithHeartbeat(time.Millisecond, func() { beats++ }); v != 1 {
+		t.Errorf("Got incorrect value; %v != 1", v)
+	}
+	if beats != 0 {
+		t.Errorf("Heartbeat fired while an element was available; %v", beats)
+	}
+
+	// Wait for a producer on another goroutine.
+	q = New[int](4)
+	go func() {
+		time.Sleep(55 * time.Millisecond)
+		q.Push(2)
+	}()
+	if v := q.PopWithHeartbeat(10*time.Millisecond, func() { beats++ }); v != 2 {
+		t.Errorf("Got incorrect value; %v != 2", v)
+	}
+	// Expect 5 beats, leaving some slack for scheduling delays.
+	if beats < 3 || beats > 6 {
+		t.Errorf("Unexpected number of heartbeats; %v not in [3, 6]", beats)
+	}
+}
@@ -0,0 +1,121 @@
+package spscqueue
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// By default the read and write indices wrap around at the end of the underlying buffer, i.e. they
+// are always valid positions in `items`. Queues created using WithMonotonicIndices instead let the
+// indices increase, mapping them onto positions in `items` when accessing the buffer. Monotonic
+// indices wrap around at a multiple of the length of `items` (`period`), such that the mapping
+// remains consistent; for power-of-two lengths this is the natural wrap-around of uint64, which is
+// represented by a period of 0. The helpers below hide the difference between the two schemes from
+// the rest of the package.
+
+// updateIndexing sets the period of the indices and the mask used to map monotonic indices onto
+// `items` according to the current length of `items`.
+func (q *Queue[T]) updateIndexing() {
+	l := uint64(len(q.items))
+	q.mask, q.period = 0, l
+	if q.monotonic {
+		if l&(l-1) == 0 {
+			q.mask, q.period = l-1, 0
+		} else {
+			q.period = l * (math.MaxUint64 / l)
+		}
+	}
+}
+
+// setIndices moves the read and write indices of a quiesced queue to `rIdx` and `wIdx`, and resets
+// the cached copies of them. The indices are still stored atomically, since threads other than the
+// producer and the consumer, such as the stall watchdog, may read them at any time.
+func (q *Queue[T]) setIndices(rIdx, wIdx uint64) {
+	atomic.StoreUint64(&q.rIdx, rIdx)
+	atomic.StoreUint64(&q.wIdx, wIdx)
+	q.wIdxCached, q.rIdxCached = wIdx, rIdx
+}
+
+// next returns the index following `idx`.
+func (q *Queue[T]) next(idx uint64) uint64 {
+	idx++
+	if idx == q.period {
+		return 0
+	}
+
+	return idx
+}
+
+// prev returns the index preceding `idx`.
+func (q *Queue[T]) prev(idx uint64) uint64 {
+	if idx == 0 {
+		// With a period of 0 the subtraction wraps around to the largest index.
+		return q.period - 1
+	}
+
+	return idx - 1
+}
+
+// add returns the index `n` positions after `idx`. `n` must not exceed the length of `items`.
+func (q *Queue[T]) add(idx, n uint64) uint64 {
+	// The comparison is arranged so as not to overflow; with a period of 0 the subtraction wraps
+	// around, which yields the correct result for the natural wrap-around of uint64.
+	if idx >= q.period-n {
+		return idx - (q.period - n)
+	}
+
+	return idx + n
+}
+
+// collides reports whether moving the producer to `wIdxNext` would run into the consumer at
+// `rIdx`, i.e. whether the queue is full.
+func (q *Queue[T]) collides(wIdxNext, rIdx uint64) bool {
+	if q.monotonic {
+		return q.length(rIdx, wIdxNext) == uint64(len(q.items))
+	}
+
+	return wIdxNext == rIdx
+}
+
+// slot returns the position in `items` referred to by `idx`.
+func (q *Queue[T]) slot(idx uint64) uint64 {
+	if !q.monotonic {
+		return idx
+	} else if q.mask != 0 {
+		return idx & q.mask
+	}
+
+	return idx % uint64(len(q.items))
+}
+
+// length returns the number of elements between the passed read and write indices.
+func (q *Queue[T]) length(rIdx, wIdx uint64) uint64 {
+	if wIdx < rIdx {
+		// The write index wrapped around; with a period of 0 the addition is a no-op.
+		return wIdx - rIdx + q.period
+	}
+
+	return wIdx - rIdx
+}
+
+// observedLength returns the number of elements between read and write indices loaded by a thread
+// which may be neither the producer nor the consumer, clamped to the capacity. Such a thread cannot
+// load both indices at once; with the read index loaded first, the consumer may have moved on
+// before the write index is loaded, so the distance between the two may exceed the capacity (or
+// even wrap around the period), but it can never be negative. All length-derived APIs therefore
+// load the read index first and use observedLength, which keeps their results in [0, Cap()].
+func (q *Queue[T]) observedLength(rIdx, wIdx uint64) uint64 {
+	if l := q.length(rIdx, wIdx); l <= q.Cap() {
+		return l
+	}
+
+	return q.Cap()
+}
+
+// IsPow2 reports whether the queue maps indices onto its underlying buffer using the power-of-two
+// mask, its fastest indexing path. This is the case for queues using monotonic indices whose
+// capacity is one less than a power of two.
+// Any thread may call IsPow2.
+func (q *Queue[T]) IsPow2() bool {
+	return q.monotonic && q.period == 0
+}
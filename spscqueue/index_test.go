@@ -0,0 +1,248 @@
+package spscqueue
+
+import (
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Single threaded test for monotonic indices, covering power-of-two and other buffer sizes.
+func TestMonotonicIndices(t *testing.T) {
+	for _, size := range []uint{7, 8} {
+		q := New[int](size, WithMonotonicIndices())
+		if q.Cap() != uint64(size) {
+			t.Errorf("Unexpected capacity; %v != %v", q.Cap(), size)
+		}
+
+		// Cycle through the buffer several times to exercise the mapping onto slots.
+		pushed, popped := 0, 0
+		for round := 0; round < 5*int(size); round++ {
+			for q.Offer(pushed) {
+				pushed++
+			}
+			if l := q.Len(); l != uint64(size) {
+				t.Errorf("Unexpected length; %v != %v", l, size)
+			}
+			for i := 0; i < 3; i++ {
+				if v := q.Pop(); v != popped {
+					t.Errorf("Got incorrect value; %v != %v", v, popped)
+				}
+				popped++
+			}
+			if l := q.Len(); l != uint64(size)-3 {
+				t.Errorf("Unexpected length; %v != %v", l, size-3)
+			}
+		}
+	}
+}
+
+// Test for detecting the power-of-two indexing path.
+func TestIsPow2(t *testing.T) {
+	if q := New[int](7, WithMonotonicIndices()); !q.IsPow2() {
+		t.Error("Queue with monotonic indices and capacity 7 not using the power-of-two path")
+	}
+	if q := New[int](100, WithMonotonicIndices()); q.IsPow2() {
+		t.Error("Queue with monotonic indices and capacity 100 using the power-of-two path")
+	}
+	if q := New[int](7); q.IsPow2() {
+		t.Error("Queue with wrapping indices using the power-of-two path")
+	}
+}
+
+// Test that stepping back from index 0 wraps around to the end of each indexing scheme.
+func TestPrevIndex(t *testing.T) {
+	for _, tc := range []struct {
+		q    *Queue[int]
+		want uint64
+	}{
+		{New[int](6), 6},
+		{New[int](7, WithMonotonicIndices()), math.MaxUint64},
+		{New[int](6, WithMonotonicIndices()), 7*(math.MaxUint64/7) - 1},
+	} {
+		if idx := tc.q.prev(0); idx != tc.want {
+			t.Errorf("Got incorrect index; %v != %v", idx, tc.want)
+		}
+		if s := tc.q.slot(tc.q.prev(0)); s != uint64(len(tc.q.items))-1 {
+			t.Errorf("Got incorrect slot; %v != %v", s, len(tc.q.items)-1)
+		}
+		if idx := tc.q.next(tc.q.prev(0)); idx != 0 {
+			t.Errorf("Got incorrect index; %v != 0", idx)
+		}
+	}
+}
+
+// SPSC test for monotonic indices, covering power-of-two and other buffer sizes.
+func TestMonotonicPushPop(t *testing.T) {
+	const numItems = 10000
+	for _, size := range []uint{63, 64} {
+		t.Run(strconv.Itoa(int(size)), func(t *testing.T) {
+			q := New[int](size, WithMonotonicIndices())
+			wg := sync.WaitGroup{}
+
+			wg.Add(1)
+			go func(wg *sync.WaitGroup) {
+				defer wg.Done()
+				for i := 0; i < numItems; i++ {
+					if i%2 == 0 {
+						q.Push(i)
+						continue
+					}
+					span := q.WritableSpan()
+					for len(span) == 0 {
+						runtime.Gosched()
+						span = q.WritableSpan()
+					}
+					span[0] = i
+					q.CommitN(1)
+				}
+			}(&wg)
+
+			wg.Add(1)
+			go func(wg *sync.WaitGroup) {
+				defer wg.Done()
+				for i := 0; i < numItems; {
+					span := q.ReadableSpan()
+					for _, v := range span {
+						if v != i {
+							t.Errorf("Got incorrect value; %v != %v", v, i)
+						}
+						i++
+					}
+					q.AdvanceN(uint64(len(span)))
+					if len(span) == 0 {
+						runtime.Gosched()
+					}
+				}
+			}(&wg)
+
+			wg.Wait()
+		})
+	}
+}
+
+// Test that indices remain consistent when they wrap around, including at the uint64 boundary.
+func TestIndexWrapAround(t *testing.T) {
+	nonPow2 := New[int](6, WithMonotonicIndices())
+	for _, tc := range []struct {
+		name  string
+		q     *Queue[int]
+		start uint64
+	}{
+		{"Wrapping", New[int](6), 4},
+		{"MonotonicPow2", New[int](7, WithMonotonicIndices()), math.MaxUint64 - 4},
+		{"MonotonicNonPow2", nonPow2, nonPow2.period - 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			q := tc.q
+			q.rIdx, q.wIdx, q.rIdxCached, q.wIdxCached = tc.start, tc.start, tc.start, tc.start
+
+			pushed, popped := 0, 0
+			for round := 0; round < 4; round++ {
+				for q.Offer(pushed) {
+					pushed++
+				}
+				if l := q.Len(); l != q.Cap() {
+					t.Errorf("Unexpected length; %v != %v", l, q.Cap())
+				}
+				for i := 0; i < 3; i++ {
+					if v := q.Pop(); v != popped {
+						t.Errorf("Got incorrect value; %v != %v", v, popped)
+					}
+					popped++
+				}
+				if l := q.Len(); l != q.Cap()-3 {
+					t.Errorf("Unexpected length; %v != %v", l, q.Cap()-3)
+				}
+			}
+
+			// Drain the queue through spans, which cross the end of the underlying buffer.
+			for span := q.ReadableSpan(); len(span) != 0; span = q.ReadableSpan() {
+				for _, v := range span {
+					if v != popped {
+						t.Errorf("Got incorrect value; %v != %v", v, popped)
+					}
+					popped++
+				}
+				q.AdvanceN(uint64(len(span)))
+			}
+			if popped != pushed {
+				t.Errorf("Unexpected number of elements; %v != %v", popped, pushed)
+			}
+			if l := q.Len(); l != 0 {
+				t.Errorf("Unexpected length; %v != 0", l)
+			}
+		})
+	}
+}
+
+// benchmarkPushPop runs the SPSC benchmark on the passed queue.
+func benchmarkPushPop(b *testing.B, q *Queue[int]) {
+	start := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer wg.Done()
+		<-start
+		for i := 0; i < b.N; i++ {
+			q.Push(i)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer wg.Done()
+		<-start
+		for i := 0; i < b.N; i++ {
+			_ = q.Pop()
+		}
+	}(&wg)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	close(start)
+
+	wg.Wait()
+}
+
+// SPSC benchmarks comparing wrapping and monotonic indices for power-of-two and other buffer sizes.
+func BenchmarkPushPopWrappingPow2(b *testing.B) {
+	benchmarkPushPop(b, New[int](1023))
+}
+
+func BenchmarkPushPopWrappingNonPow2(b *testing.B) {
+	benchmarkPushPop(b, New[int](1024))
+}
+
+func BenchmarkPushPopMonotonicPow2(b *testing.B) {
+	benchmarkPushPop(b, New[int](1023, WithMonotonicIndices()))
+}
+
+func BenchmarkPushPopMonotonicNonPow2(b *testing.B) {
+	benchmarkPushPop(b, New[int](1024, WithMonotonicIndices()))
+}
+
+// Test that rounded-up capacities are reported.
+func TestNewPow2(t *testing.T) {
+	for _, tc := range []struct {
+		minSize  uint
+		capacity uint
+	}{{0, 0}, {1, 1}, {2, 3}, {100, 127}, {127, 127}, {128, 255}} {
+		q, capacity := NewPow2[int](tc.minSize)
+		if capacity != tc.capacity {
+			t.Errorf("Got incorrect capacity for %v; %v != %v", tc.minSize, capacity, tc.capacity)
+		}
+		if c := q.Cap(); c != uint64(capacity) {
+			t.Errorf("Got incorrect capacity; %v != %v", c, capacity)
+		}
+		if !q.IsPow2() {
+			t.Errorf("Queue of capacity %v does not use power-of-two indexing", capacity)
+		}
+	}
+}
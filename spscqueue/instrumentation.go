@@ -0,0 +1,43 @@
+package spscqueue
+
+import (
+	"math/bits"
+	"sync/atomic"
+)
+
+// spinHistogramBuckets is the number of buckets in a spin histogram; one for operations that did
+// not spin and one for each power of two up to the maximum spin count.
+const spinHistogramBuckets = 65
+
+// spinHistogram counts operations by the number of spins they incurred. It is written by a single
+// goroutine (the producer or the consumer) and may be read by any goroutine.
+type spinHistogram struct {
+	_       cacheLinePad
+	buckets [spinHistogramBuckets]uint64
+	_       cacheLinePad
+}
+
+// record adds an operation which incurred `spins` spins to the histogram.
+func (h *spinHistogram) record(spins uint64) {
+	b := bits.Len64(spins)
+	atomic.StoreUint64(&h.buckets[b], h.buckets[b]+1)
+}
+
+// SpinHistogram returns the number of Push and Pop operations bucketed by the number of spins they
+// incurred while waiting for the other side. Bucket 0 counts operations which did not have to
+// wait, and bucket i > 0 counts operations which spun between 2^(i-1) and 2^i - 1 times.
+// SpinHistogram returns nil unless the queue was created using WithSpinInstrumentation.
+// Any thread may call SpinHistogram.
+func (q *Queue[T]) SpinHistogram() []uint64 {
+	if q.producerSpins == nil {
+		return nil
+	}
+
+	ret := make([]uint64, spinHistogramBuckets)
+	for i := range ret {
+		ret[i] = atomic.LoadUint64(&q.producerSpins.buckets[i]) +
+			atomic.LoadUint64(&q.consumerSpins.buckets[i])
+	}
+
+	return ret
+}
@@ -0,0 +1,54 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for the spin histogram under contention.
+func TestSpinHistogram(t *testing.T) {
+	if h := New[int](1).SpinHistogram(); h != nil {
+		t.Errorf("Got histogram from uninstrumented queue; %v", h)
+	}
+
+	const numItems = 20
+	q := New[int](1, WithSpinInstrumentation())
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			q.Push(i)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			time.Sleep(time.Millisecond)
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+
+	h := q.SpinHistogram()
+	var total, high uint64
+	for i, n := range h {
+		total += n
+		if i >= 4 {
+			high += n
+		}
+	}
+	if total != 2*numItems {
+		t.Errorf("Unexpected number of recorded operations; %v != %v", total, 2*numItems)
+	}
+	if high == 0 {
+		t.Errorf("No high-spin operations recorded; %v", h)
+	}
+}
@@ -0,0 +1,62 @@
+package spscqueue
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONStream decodes a stream of concatenated JSON values from a byte queue.
+type JSONStream struct {
+	dec *json.Decoder
+}
+
+// NewJSONStream returns a JSONStream which decodes the bytes of `q`. The goroutine using the
+// JSONStream acts as the consumer of the queue.
+func NewJSONStream(q *Queue[byte]) *JSONStream {
+	return &JSONStream{json.NewDecoder(&byteReader{q})}
+}
+
+// Decode reads the next JSON value from the queue and stores it in the value pointed to by `v`, as
+// described for json.Decoder. Decode blocks while it needs more bytes than the queue holds, and
+// returns io.EOF once the queue is closed and drained at a value boundary. The underlying decoder
+// reads ahead, so bytes beyond the decoded value may already have been removed from the queue.
+// Decode should be called by the consumer.
+func (s *JSONStream) Decode(v any) error {
+	return s.dec.Decode(v)
+}
+
+// byteReader implements io.Reader on a byte queue, copying the readable spans of the queue directly
+// into the buffer passed to Read.
+type byteReader struct {
+	q *Queue[byte]
+}
+
+// Read copies up to len(p) bytes from the front of the queue into `p` and removes them. Read blocks
+// while the queue is empty, and returns io.EOF once it is closed and drained.
+// Read should be called by the consumer.
+func (r *byteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	var spins uint64
+	span := r.q.ReadableSpan()
+	for len(span) == 0 {
+		// The producer closes the queue after its last write, so re-check for data after observing
+		// the closure.
+		if r.q.Closed() {
+			if span = r.q.ReadableSpan(); len(span) == 0 {
+				return 0, io.EOF
+			}
+			break
+		}
+		r.q.waitStrategy().Wait(spins)
+		spins++
+		span = r.q.ReadableSpan()
+	}
+
+	n := copy(p, span)
+	r.q.AdvanceN(uint64(n))
+
+	return n, nil
+}
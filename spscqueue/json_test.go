@@ -0,0 +1,39 @@
+package spscqueue
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// Test for decoding JSON values from a byte queue, including a value which wraps around.
+func TestJSONStream(t *testing.T) {
+	type msg struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	q := New[byte](32)
+	s := NewJSONStream(q)
+
+	// The second value wraps around the end of the underlying buffer.
+	go func() {
+		for _, c := range []byte(`{"id":1,"name":"first"} {"id":2,"name":"second"}`) {
+			q.Push(c)
+		}
+		q.Close()
+	}()
+
+	for _, want := range []msg{{1, "first"}, {2, "second"}} {
+		var got msg
+		if err := s.Decode(&got); err != nil {
+			t.Fatalf("Unexpected error; %v", err)
+		}
+		if got != want {
+			t.Errorf("Got incorrect value; %+v != %+v", got, want)
+		}
+	}
+	var got msg
+	if err := s.Decode(&got); !errors.Is(err, io.EOF) {
+		t.Errorf("Unexpected error; %v != %v", err, io.EOF)
+	}
+}
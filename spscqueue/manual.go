@@ -0,0 +1,54 @@
+package spscqueue
+
+import (
+	"fmt"
+)
+
+// ManualScheduler is a WaitStrategy for deterministic tests which run the producer and the
+// consumer on a single goroutine. Rather than waiting for the other side to make progress, a
+// blocked operation runs the next step scheduled using Schedule, e.g. a function which pops an
+// element from a full queue, and retries once the step returns. Steps run in the order in which
+// they were scheduled, each at most once, so a test determines the exact interleaving of the
+// producer and the consumer without relying on the timing of goroutines. Blocking with no step
+// scheduled panics, since the operation would never return. The zero value is ready for use.
+// Unlike other wait strategies, a ManualScheduler must not be used concurrently.
+type ManualScheduler struct {
+	steps []func()
+}
+
+// WithManualScheduler makes blocking operations drive the queue using `s` rather than waiting.
+// It is equivalent to WithWaitStrategy(s).
+func WithManualScheduler(s *ManualScheduler) Option {
+	return WithWaitStrategy(s)
+}
+
+// Schedule appends `steps` to the steps which blocked operations run, in order, instead of waiting.
+func (s *ManualScheduler) Schedule(steps ...func()) {
+	s.steps = append(s.steps, steps...)
+}
+
+// Step runs the next scheduled step, as a blocked operation would, and returns whether there was
+// one to run. Step lets the test drive progress explicitly between non-blocking operations.
+func (s *ManualScheduler) Step() bool {
+	if len(s.steps) == 0 {
+		return false
+	}
+	step := s.steps[0]
+	s.steps[0] = nil
+	s.steps = s.steps[1:]
+	step()
+
+	return true
+}
+
+// Pending returns the number of scheduled steps which have not run yet.
+func (s *ManualScheduler) Pending() int {
+	return len(s.steps)
+}
+
+// Wait implements WaitStrategy.
+func (s *ManualScheduler) Wait(spins uint64) {
+	if !s.Step() {
+		panic(fmt.Sprintf("spscqueue: blocked with no step scheduled after %v waits", spins))
+	}
+}
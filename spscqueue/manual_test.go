@@ -0,0 +1,60 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for reproducing an interleaving of the producer and the consumer on a single goroutine.
+func TestManualScheduler(t *testing.T) {
+	s := &ManualScheduler{}
+	q := New[int](2, WithManualScheduler(s))
+	var popped []int
+	pop := func() { popped = append(popped, q.Pop()) }
+
+	// The third push blocks on the full queue until the consumer runs.
+	q.Push(1)
+	q.Push(2)
+	s.Schedule(pop)
+	q.Push(3)
+	if n := s.Pending(); n != 0 {
+		t.Errorf("Unexpected number of pending steps; %v != 0", n)
+	}
+
+	// Steps run in order and only when an operation blocks or the test steps explicitly.
+	s.Schedule(func() { q.Push(4) }, pop)
+	pop()
+	pop()
+	pop()
+	if n := s.Pending(); n != 1 {
+		t.Errorf("Unexpected number of pending steps; %v != 1", n)
+	}
+	if !q.Offer(5) {
+		t.Error("Failed to add element to empty queue")
+	}
+	if !s.Step() {
+		t.Error("Failed to run scheduled step")
+	}
+	if n := s.Pending(); n != 0 {
+		t.Errorf("Unexpected number of pending steps; %v != 0", n)
+	}
+	if s.Step() {
+		t.Error("Ran step which was not scheduled")
+	}
+
+	want := []int{1, 2, 3, 4, 5}
+	if len(popped) != len(want) {
+		t.Fatalf("Unexpected length; %v != %v", len(popped), len(want))
+	}
+	for i, v := range popped {
+		if v != want[i] {
+			t.Errorf("Got incorrect value; %v != %v", v, want[i])
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Blocking with no step scheduled did not panic")
+		}
+	}()
+	q.Pop()
+}
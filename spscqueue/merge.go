@@ -0,0 +1,27 @@
+package spscqueue
+
+// PopMerge removes the oldest element in the queue and folds the elements following it into it.
+// For each following element, PopMerge calls `merge` with the value accumulated so far and the
+// element; if `merge` returns true, the element is removed and the returned value becomes the new
+// accumulated value. PopMerge stops at the first element which `merge` rejects, which remains in
+// the queue, or once the queue is empty, and returns the accumulated value. PopMerge does not
+// block; if the queue is empty, it returns the zero-value for the type and false.
+// PopMerge should be called by the consumer.
+func (q *Queue[T]) PopMerge(merge func(acc, next T) (T, bool)) (T, bool) {
+	acc, ok := q.Front()
+	if !ok {
+		return acc, false
+	}
+	q.Advance()
+
+	for next, ok := q.Front(); ok; next, ok = q.Front() {
+		combined, merged := merge(acc, next)
+		if !merged {
+			break
+		}
+		acc = combined
+		q.Advance()
+	}
+
+	return acc, true
+}
@@ -0,0 +1,36 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for merging runs of samples with the same key.
+func TestPopMerge(t *testing.T) {
+	type sample struct {
+		key   string
+		count int
+	}
+	merge := func(acc, next sample) (sample, bool) {
+		if acc.key != next.key {
+			return acc, false
+		}
+		return sample{acc.key, acc.count + next.count}, true
+	}
+
+	q := New[sample](8)
+	if _, ok := q.PopMerge(merge); ok {
+		t.Error("Managed to pop element of empty queue")
+	}
+
+	for _, s := range []sample{{"a", 1}, {"a", 2}, {"a", 3}, {"b", 1}, {"a", 1}, {"a", 1}} {
+		q.Push(s)
+	}
+	for _, want := range []sample{{"a", 6}, {"b", 1}, {"a", 2}} {
+		if got, ok := q.PopMerge(merge); !ok || got != want {
+			t.Errorf("Got incorrect value; %v != %v", got, want)
+		}
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
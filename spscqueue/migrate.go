@@ -0,0 +1,84 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// A queue cannot change its capacity in place without stopping both sides, since the producer and
+// the consumer access the underlying buffer without synchronising with each other; Resize thus
+// requires the queue to be quiesced. BeginResize and the Migrate methods of the role handles
+// instead move both sides over to a new queue cooperatively, one side at a time:
+//
+//  1. Any thread calls BeginResize, which creates the new queue.
+//  2. The producer calls Producer.Migrate at a point between two operations. Once a new queue
+//     exists, Migrate closes the old queue and switches the handle over, so that subsequent
+//     elements are added to the new queue.
+//  3. The consumer calls Consumer.Migrate whenever it finds the queue empty. Once the producer has
+//     closed the old queue and the consumer has drained it, Migrate switches the handle over.
+//
+// Since the consumer only switches once it has removed every element the producer added to the old
+// queue, no element is lost or reordered by the migration.
+
+// BeginResize starts migrating the queue to a new queue of capacity `newSize`, created using the
+// same Options as the queue, and returns the new queue. A queue created using WithEventfd shares
+// its eventfd with the new queue. If a migration has already been started, BeginResize returns the
+// queue created by the earlier call instead, discarding the queue it created itself. The queue
+// itself is left unchanged; the role handles move over to the new queue through their Migrate
+// methods.
+// Any thread may call BeginResize.
+func (q *Queue[T]) BeginResize(newSize uint) *Queue[T] {
+	next := &Queue[T]{}
+	opts := q.opts
+	if q.event != nil {
+		// Keep the existing eventfd, which the consumer has already registered.
+		opts = append(opts[:len(opts):len(opts)], func(o *options) { o.eventfd = false })
+	}
+	next.init(make([]T, newSize+1), opts)
+	next.event = q.event
+
+	if !q.successor.CompareAndSwap(nil, next) {
+		// Discard the new queue, closing it so that its stall watchdog, if any, exits.
+		atomic.StoreUint32(&next.closed, 1)
+		return q.successor.Load().(*Queue[T])
+	}
+	return next
+}
+
+// nextQueue returns the queue created by BeginResize, or nil if no migration has been started.
+func (q *Queue[T]) nextQueue() *Queue[T] {
+	next, _ := q.successor.Load().(*Queue[T])
+	return next
+}
+
+// Migrate moves the producer over to the queue created by BeginResize, if any, and returns whether
+// it did. The old queue is closed, signalling to the consumer that it will not receive any more
+// elements. Migrate should be called between operations, not during a Reserve-Commit sequence or
+// while slots reserved through ReserveN or ReserveSlot are outstanding.
+func (p *Producer[T]) Migrate() bool {
+	next := p.q.nextQueue()
+	if next == nil {
+		return false
+	}
+	p.q.Close()
+	p.q = next
+
+	return true
+}
+
+// Migrate moves the consumer over to the queue created by BeginResize, once the producer has moved
+// over and the consumer has removed every element from the old queue, and returns whether it did.
+// Migrate should be called whenever the consumer finds the queue empty.
+func (c *Consumer[T]) Migrate() bool {
+	next := c.q.nextQueue()
+	if next == nil || !c.q.Closed() {
+		return false
+	}
+	// The producer closes the old queue after its last write, so re-check for elements after
+	// observing the closure.
+	if _, ok := c.q.Front(); ok {
+		return false
+	}
+	c.q = next
+
+	return true
+}
@@ -0,0 +1,92 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test that migrating to a larger queue neither loses nor reorders elements.
+func TestMigrate(t *testing.T) {
+	const numItems = 10000
+	q := New[int](8, WithStats())
+	p, c := q.Producer(), q.Consumer()
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		migrated := false
+		for i := 0; i < numItems; i++ {
+			if !migrated {
+				migrated = p.Migrate()
+			}
+			p.Push(i)
+		}
+		if !migrated {
+			t.Error("Producer did not migrate")
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if i == numItems/4 {
+				next := q.BeginResize(64)
+				if again := q.BeginResize(128); again != next {
+					t.Error("Repeated BeginResize created another queue")
+				}
+			}
+			v, ok := c.Front()
+			for !ok {
+				c.Migrate()
+				runtime.Gosched()
+				v, ok = c.Front()
+			}
+			if v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+			c.Advance()
+		}
+	}(&wg)
+
+	wg.Wait()
+
+	next := q.BeginResize(0)
+	if capacity := next.Cap(); capacity != 64 {
+		t.Errorf("Unexpected capacity; %v != 64", capacity)
+	}
+	if !next.stats {
+		t.Error("Options not carried over to the new queue")
+	}
+	if !q.Closed() || q.Len() != 0 {
+		t.Errorf("Old queue not closed and drained; %v", q.Len())
+	}
+	if p.q != next || c.q != next {
+		t.Error("Role handles not moved over to the new queue")
+	}
+}
+
+// Test that a repeated BeginResize does not leave the watchdog of its discarded queue running.
+func TestBeginResizeWatchdog(t *testing.T) {
+	q := New[int](4, WithStallWatchdog(time.Millisecond, func(time.Duration) {}))
+	next := q.BeginResize(8)
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		if again := q.BeginResize(16); again != next {
+			t.Error("Repeated BeginResize created another queue")
+		}
+	}
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Watchdogs of discarded queues did not exit; %v > %v goroutines",
+				runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	q.Close()
+	next.Close()
+}
@@ -0,0 +1,34 @@
+package spscqueue
+
+// Mux consumes from several queues in round-robin order, e.g. to serve one queue per producer
+// fairly from a single consumer.
+type Mux[T any] struct {
+	queues []*Queue[T]
+	next   int
+}
+
+// NewMux returns a Mux which consumes from `queues`. The goroutine using the Mux acts as the
+// consumer of each of the queues.
+func NewMux[T any](queues ...*Queue[T]) *Mux[T] {
+	return &Mux[T]{queues: queues}
+}
+
+// Next removes and returns the oldest element of the next non-empty queue in round-robin order,
+// along with the index of that queue among those passed to NewMux. The queue after it is checked
+// first by the following call to Next, so that no queue is starved by the others. A boolean
+// indicator of success or failure is included as a third return value; if all queues are empty,
+// Next returns the zero-value for the type, -1 and false. Next does not block.
+// Next should be called by the consumer.
+func (m *Mux[T]) Next() (T, int, bool) {
+	for i := range m.queues {
+		idx := (m.next + i) % len(m.queues)
+		if el, ok := m.queues[idx].Front(); ok {
+			m.queues[idx].Advance()
+			m.next = (idx + 1) % len(m.queues)
+			return el, idx, true
+		}
+	}
+
+	var t T
+	return t, -1, false
+}
@@ -0,0 +1,36 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for round-robin consumption from queues at different fill levels.
+func TestMux(t *testing.T) {
+	qs := []*Queue[int]{New[int](8), New[int](8), New[int](8)}
+	for i, n := range []int{3, 1, 2} {
+		for j := 0; j < n; j++ {
+			qs[i].Push(10*i + j)
+		}
+	}
+
+	m := NewMux(qs...)
+	want := []struct{ v, src int }{{0, 0}, {10, 1}, {20, 2}, {1, 0}, {21, 2}, {2, 0}}
+	for _, w := range want {
+		v, src, ok := m.Next()
+		if !ok || v != w.v || src != w.src {
+			t.Errorf("Got incorrect value; %v, %v, %v != %v, %v, true", v, src, ok, w.v, w.src)
+		}
+	}
+	if v, src, ok := m.Next(); ok || v != 0 || src != -1 {
+		t.Errorf("Got value from empty queues; %v, %v", v, src)
+	}
+
+	// Rotation resumes after the queue which was served last.
+	qs[1].Push(11)
+	qs[0].Push(3)
+	for _, w := range []struct{ v, src int }{{11, 1}, {3, 0}} {
+		if v, src, ok := m.Next(); !ok || v != w.v || src != w.src {
+			t.Errorf("Got incorrect value; %v, %v, %v != %v, %v, true", v, src, ok, w.v, w.src)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package spscqueue
+
+// OfferSpin adds the passed element to the queue like Offer, but if the queue is full, retries up
+// to `maxSpins` times, invoking the wait strategy before each retry, before giving up. This bounds
+// the effort the producer spends on enqueueing, between Offer, which gives up at once, and Push,
+// which blocks until a slot is available. A non-positive `maxSpins` makes OfferSpin equivalent to
+// Offer. OfferSpin returns whether the element was added; it gives up without retrying if the
+// queue has been closed. Only an element which OfferSpin gives up on is counted by DroppedCount,
+// once, regardless of the number of attempts.
+// OfferSpin should be called by the producer.
+func (q *Queue[T]) OfferSpin(el T, maxSpins int) bool {
+	q.checkProducer()
+	for spins := 0; ; spins++ {
+		if q.offerFast(el) || q.tryOffer(el) {
+			return true
+		} else if spins >= maxSpins || q.closed != 0 {
+			q.recordDrop()
+			return false
+		}
+		q.waitStrategy().Wait(uint64(spins))
+	}
+}
@@ -0,0 +1,72 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for offering elements with a bounded number of retries to a slow consumer.
+func TestOfferSpin(t *testing.T) {
+	q := New[int](1)
+	if !q.OfferSpin(1, 0) {
+		t.Error("Failed to add element to empty queue")
+	}
+	if q.OfferSpin(2, -1) {
+		t.Error("Added element to full queue")
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		if v := q.Pop(); v != 1 {
+			t.Errorf("Got incorrect value; %v != 1", v)
+		}
+	}(&wg)
+
+	if q.OfferSpin(2, 4) {
+		t.Error("Added element to full queue with a small budget")
+	}
+	if !q.OfferSpin(2, 1<<30) {
+		t.Error("Failed to add element with a large budget")
+	}
+	wg.Wait()
+	if l := q.Len(); l != 1 {
+		t.Errorf("Unexpected length; %v != 1", l)
+	}
+
+	q.Close()
+	if q.OfferSpin(3, 1<<30) {
+		t.Error("Added element to closed queue")
+	}
+}
+
+// Test that OfferSpin counts a single drop per element it gives up on, and none on success.
+func TestOfferSpinDropped(t *testing.T) {
+	q := New[int](1)
+	q.Push(1)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		q.Pop()
+	}(&wg)
+	if !q.OfferSpin(2, 1<<30) {
+		t.Error("Failed to add element with a large budget")
+	}
+	wg.Wait()
+	if n := q.DroppedCount(); n != 0 {
+		t.Errorf("Unexpected dropped count; %v != 0", n)
+	}
+
+	if q.OfferSpin(3, 16) {
+		t.Error("Added element to full queue")
+	}
+	if n := q.DroppedCount(); n != 1 {
+		t.Errorf("Unexpected dropped count; %v != 1", n)
+	}
+}
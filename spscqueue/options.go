@@ -0,0 +1,104 @@
+package spscqueue
+
+import (
+	"time"
+)
+
+// Option configures optional behaviour of a queue. Options are passed to New.
+type Option func(*options)
+
+// options holds the configuration assembled from the Options passed to New.
+type options struct {
+	spinInstrumentation bool
+	stats               bool
+	monotonic           bool
+	blockCallback       func(time.Duration)
+	lazyFill            any
+	waitStrategy        WaitStrategy
+	eventfd             bool
+	futex               bool
+	dwell               bool
+	firstTouch          Role
+	diagnostics         bool
+	seqLen              bool
+	reclaim             any
+	maxStall            time.Duration
+	onStall             func(time.Duration)
+	deadlockTimeout     time.Duration
+}
+
+// WithSpinInstrumentation enables recording of the number of spins each Push and Pop incurred
+// before succeeding. The recorded data is available through SpinHistogram.
+func WithSpinInstrumentation() Option {
+	return func(o *options) {
+		o.spinInstrumentation = true
+	}
+}
+
+// WithStats enables tracking of the statistics reported by HighWaterMark, Stats, WrapCounts and
+// SpinStats.
+func WithStats() Option {
+	return func(o *options) {
+		o.stats = true
+	}
+}
+
+// WithMonotonicIndices makes the queue track its read and write positions using indices which never
+// wrap around, mapping them onto the underlying buffer when it is accessed. This removes the
+// wrap-around branch from the index updates, at the cost of a modulo operation per access. The
+// modulo is replaced by a cheaper mask when the size of the underlying buffer (`size`+1) is a power
+// of two. To keep the mapping consistent, the indices wrap around at the largest multiple of the
+// buffer size which fits into a uint64.
+func WithMonotonicIndices() Option {
+	return func(o *options) {
+		o.monotonic = true
+	}
+}
+
+// WithBlockCallback registers a function which is called each time Push had to wait for the consumer
+// to free up a slot. The callback is passed the time spent waiting, and is run on the producer's
+// goroutine before Push returns.
+func WithBlockCallback(f func(time.Duration)) Option {
+	return func(o *options) {
+		o.blockCallback = f
+	}
+}
+
+// WithLazyFill makes Reserve initialise each slot of the queue using `gen` the first time the slot
+// is handed out, and reuse the slot's contents thereafter. This has the same effect as calling Fill
+// after construction, but spreads the cost of initialisation over the lifetime of the queue.
+// The type returned by `gen` must be the element type of the queue; New panics otherwise.
+func WithLazyFill[T any](gen func() T) Option {
+	return func(o *options) {
+		o.lazyFill = gen
+	}
+}
+
+// WithWaitStrategy sets the WaitStrategy used by blocking operations. The strategy may later be
+// changed using SetWaitStrategy. By default
+// AdaptiveWait{DefaultAdaptiveSpins, DefaultAdaptiveYields, DefaultAdaptiveSleep} is used.
+func WithWaitStrategy(s WaitStrategy) Option {
+	return func(o *options) {
+		o.waitStrategy = s
+	}
+}
+
+// WithFirstTouch hands the first write to the pages of the queue's underlying buffer to `role`,
+// which does so by calling TouchFromProducer or TouchFromConsumer respectively before the queue is
+// used; the touch method of the other role becomes a no-op, so both sides may call theirs
+// unconditionally. See TouchFromProducer for the rationale.
+func WithFirstTouch(role Role) Option {
+	return func(o *options) {
+		o.firstTouch = role
+	}
+}
+
+// WithDiagnostics makes the queue record the call stacks at which the producer and consumer roles
+// were first used, which DiagInfo reports and which are included in the panic raised when a role
+// migrates to another goroutine. Diagnostics build on the role checks of the spscqueue_debug build
+// tag; without the build tag, WithDiagnostics has no effect and costs nothing.
+func WithDiagnostics() Option {
+	return func(o *options) {
+		o.diagnostics = true
+	}
+}
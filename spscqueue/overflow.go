@@ -0,0 +1,69 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// WithOverflow chains `secondary` to the queue as an overflow queue. Once the queue is full, Push
+// adds elements to `secondary` instead of blocking, and Pop removes elements from `secondary` once
+// the queue is empty, such that the chained queues behave as a single queue whose capacity is the
+// sum of theirs. Push only blocks once both queues are full.
+// The elements are removed in the order in which they were added across both queues: as long as
+// `secondary` holds elements, Push keeps adding new elements to it rather than to the queue, since
+// those elements must be removed after the ones already in `secondary`.
+// Only Push and Pop, and the context-aware methods building on them, take `secondary` into
+// account; the producer and the consumer of the queue take on the same roles for `secondary`,
+// which must not be used otherwise. Len and the other methods of the queue only report on the
+// queue itself. WithOverflow must be called before the queue is used.
+func (q *Queue[T]) WithOverflow(secondary *Queue[T]) {
+	q.overflow = secondary
+	q.fastPaths()
+}
+
+// pushOverflow implements Push for queues with an overflow queue.
+func (q *Queue[T]) pushOverflow(el T) {
+	sec := q.overflow
+	// Elements must be added behind those still waiting in the overflow queue.
+	if atomic.LoadUint64(&sec.rIdx) != sec.wIdx {
+		sec.Push(el)
+		return
+	}
+
+	wIdxNext := q.next(q.wIdx)
+	if q.collides(wIdxNext, q.rIdxCached) {
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+		if q.collides(wIdxNext, q.rIdxCached) {
+			sec.Push(el)
+			return
+		}
+	}
+	if q.reclaim != nil {
+		q.reclaimSlots(q.wIdx, 1)
+	}
+	q.items[q.slot(q.wIdx)] = el
+	q.publish(wIdxNext)
+}
+
+// popOverflow implements Pop for queues with an overflow queue.
+func (q *Queue[T]) popOverflow() T {
+	sec := q.overflow
+	var spins uint64
+	for {
+		if el, ok := q.Front(); ok {
+			q.Advance()
+			return el
+		}
+		if _, ok := sec.Front(); ok {
+			// The producer may have added elements to the queue after the check above, and then
+			// more elements to the overflow queue once the queue was full; the former must be
+			// removed first.
+			if el, ok := q.Front(); ok {
+				q.Advance()
+				return el
+			}
+			return sec.Pop()
+		}
+		q.waitStrategy().Wait(spins)
+		spins++
+	}
+}
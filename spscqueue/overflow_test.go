@@ -0,0 +1,66 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// Test for spilling over into an overflow queue in order.
+func TestWithOverflow(t *testing.T) {
+	q := New[int](4)
+	q.WithOverflow(New[int](8))
+
+	// Overflow the primary queue, then drain part of it while the overflow queue holds elements.
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	if l := q.Len(); l != 4 {
+		t.Errorf("Unexpected length; %v != 4", l)
+	}
+	for i := 0; i < 2; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+	// There is room in the primary queue again, but the elements must go behind the overflow.
+	for i := 10; i < 12; i++ {
+		q.Push(i)
+	}
+	if l := q.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+	for i := 2; i < 12; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+}
+
+// SPSC test for the global FIFO order across the chained queues.
+func TestWithOverflowPushPop(t *testing.T) {
+	const numItems = 100000
+	q := New[int](3)
+	q.WithOverflow(New[int](5))
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			q.Push(i)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+				return
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+}
@@ -0,0 +1,29 @@
+package spscqueue
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/cpu"
+)
+
+// minCacheLineSize is the smallest padding used to separate fields onto different cache lines. It
+// covers the cache line size of all common platforms, including those for which cpu.CacheLinePad
+// is smaller, e.g. because golang.org/x/sys/cpu falls back to a conservative default for them.
+const minCacheLineSize = 64
+
+// cacheLineCount is the number of minCacheLineSize lines which cpu.CacheLinePad spans, rounded up,
+// but at least one since cpu.CacheLinePad is empty on some platforms, e.g. js/wasm. For a size n,
+// (n-1)/minCacheLineSize+1 rounds up like the usual (n+minCacheLineSize-1)/minCacheLineSize, except
+// for n == 0: integer division truncates towards zero, so -1/minCacheLineSize is 0 and the count is
+// one rather than zero. The size is converted to int so that n-1 may be negative.
+const cacheLineCount = (int(unsafe.Sizeof(cpu.CacheLinePad{}))-1)/minCacheLineSize + 1
+
+// cacheLinePadSize is the size of cpu.CacheLinePad rounded up to a multiple of minCacheLineSize,
+// and at least minCacheLineSize, so that it is never smaller than either of them. Cache line sizes
+// are powers of two, so rounding up leaves cpu.CacheLinePad unchanged on platforms with cache
+// lines of at least minCacheLineSize.
+const cacheLinePadSize = cacheLineCount * minCacheLineSize
+
+// cacheLinePad pads structs to ensure that the fields which follow it do not share a cache line with
+// those preceding it.
+type cacheLinePad struct{ _ [cacheLinePadSize]byte }
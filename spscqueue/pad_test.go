@@ -0,0 +1,71 @@
+package spscqueue
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"golang.org/x/sys/cpu"
+)
+
+// Test that the fields written by the consumer and those written by the producer do not share a
+// cache line.
+func TestCacheLineSeparation(t *testing.T) {
+	if s := unsafe.Sizeof(cacheLinePad{}); s < minCacheLineSize {
+		t.Errorf("Unexpected padding size; %v < %v", s, minCacheLineSize)
+	}
+	if s, c := unsafe.Sizeof(cacheLinePad{}), unsafe.Sizeof(cpu.CacheLinePad{}); s < c {
+		t.Errorf("Unexpected padding size; %v < %v", s, c)
+	}
+
+	var q Queue[int]
+	consumer := unsafe.Offsetof(q.rIdx)
+	consumerEnd := unsafe.Offsetof(q.dwellMax) + unsafe.Sizeof(q.dwellMax)
+	producer := unsafe.Offsetof(q.wIdx)
+	if d := producer - unsafe.Offsetof(q.rIdx); d < minCacheLineSize {
+		t.Errorf("Read and write indices too close; %v < %v", d, minCacheLineSize)
+	}
+	if d := producer - consumerEnd; d < minCacheLineSize {
+		t.Errorf("Consumer and producer fields too close; %v < %v", d, minCacheLineSize)
+	}
+	if d := consumer - unsafe.Offsetof(q.wait) - unsafe.Sizeof(q.wait); d < minCacheLineSize {
+		t.Errorf("Consumer fields too close to shared fields; %v < %v", d, minCacheLineSize)
+	}
+}
+
+// crossPlatforms are the GOOS/GOARCH pairs the package is built for by TestCrossCompile. They
+// include platforms whose cpu.CacheLinePad is empty or has an unusual size, and each variant of the
+// eventfd, futex and pause implementations.
+var crossPlatforms = []string{
+	"linux/amd64", "linux/arm64", "linux/386", "linux/arm", "linux/riscv64", "linux/s390x",
+	"linux/ppc64le", "linux/mips", "darwin/arm64", "windows/amd64", "freebsd/amd64", "js/wasm",
+	"wasip1/wasm",
+}
+
+// crossCompileEnv is the environment variable which enables TestCrossCompile.
+const crossCompileEnv = "SPSCQUEUE_CROSS_COMPILE"
+
+// Test that the package builds for other platforms. Building for all of them takes a while and
+// requires the go tool, so the test only runs if crossCompileEnv is set to 1.
+func TestCrossCompile(t *testing.T) {
+	if os.Getenv(crossCompileEnv) != "1" {
+		t.Skipf("Cross-compiling is only tested if %v=1", crossCompileEnv)
+	}
+	goTool := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goTool); err != nil {
+		t.Skipf("Go tool not available; %v", err)
+	}
+
+	for _, platform := range crossPlatforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		cmd := exec.Command(goTool, "build", ".")
+		cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH="+goarch, "CGO_ENABLED=0")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Failed to build for %v; %v\n%s", platform, err, out)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !purego
+
+package spscqueue
+
+import (
+	"runtime"
+	"testing"
+)
+
+// noPauseSpinWait busy-spins like SpinWait, but without issuing PAUSE, as a reference for the effect
+// of the spin hint.
+type noPauseSpinWait struct{}
+
+func (noPauseSpinWait) Wait(uint64) {}
+
+// Round-trip latency benchmarks for busy-spinning with and without PAUSE. Both sides spin on
+// dedicated threads, so they are skipped unless GOMAXPROCS >= 2.
+func BenchmarkLatencySpinPause(b *testing.B) {
+	skipSingleProc(b)
+	benchmarkLatency(b, SpinWait{})
+}
+
+func BenchmarkLatencySpinNoPause(b *testing.B) {
+	skipSingleProc(b)
+	benchmarkLatency(b, noPauseSpinWait{})
+}
+
+func skipSingleProc(b *testing.B) {
+	if runtime.GOMAXPROCS(0) < 2 {
+		b.Skip("busy-spinning requires GOMAXPROCS >= 2")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build (amd64 || arm64) && !purego
+
+package spscqueue
+
+// pause hints to the processor that the caller is busy-waiting, using PAUSE on amd64 and YIELD on
+// arm64. This reduces the power draw of the spin and the penalty for leaving it, and lets a
+// hyperthread sibling make progress in the meantime.
+func pause()
@@ -0,0 +1,9 @@
+//go:build (!amd64 && !arm64) || purego
+
+package spscqueue
+
+// pause briefly delays the caller. It is not inlined, so that loops calling it are not optimised
+// away. Building with the purego tag selects this portable implementation on all architectures.
+//
+//go:noinline
+func pause() {}
@@ -0,0 +1,43 @@
+package spscqueue
+
+import (
+	"runtime"
+)
+
+// Pipe moves elements from `src` to `dst`, applying `f` to each one, until `done` is closed. Pipe
+// blocks while `src` is empty or `dst` is full, and returns once it observes that `done` is
+// closed. An element is only removed from `src` once its transformed value has been added to
+// `dst`, so an element being processed when `done` is closed remains at the front of `src`.
+// Pipe acts as the consumer of `src` and as the producer of `dst`; no other goroutine may take on
+// either role while Pipe is running.
+func Pipe[A, B any](src *Queue[A], dst *Queue[B], f func(A) B, done <-chan struct{}) {
+	for {
+		a, ok := src.Front()
+		for !ok {
+			if isDone(done) {
+				return
+			}
+			runtime.Gosched()
+			a, ok = src.Front()
+		}
+
+		b := f(a)
+		for !dst.Offer(b) {
+			if isDone(done) {
+				return
+			}
+			runtime.Gosched()
+		}
+		src.Advance()
+	}
+}
+
+// isDone reports whether `done` is closed without blocking.
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package spscqueue
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// Test for a pipeline of two Pipe stages.
+func TestPipe(t *testing.T) {
+	const numItems = 10000
+	src := New[int](16)
+	mid := New[string](8)
+	dst := New[int](32)
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		Pipe(src, mid, strconv.Itoa, done)
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		Pipe(mid, dst, func(s string) int { return len(s) }, done)
+	}(&wg)
+
+	go func() {
+		for i := 0; i < numItems; i++ {
+			src.Push(i)
+		}
+	}()
+
+	for i := 0; i < numItems; i++ {
+		v := dst.Pop()
+		if e := len(strconv.Itoa(i)); v != e {
+			t.Errorf("Got incorrect value; %v != %v", v, e)
+		}
+	}
+
+	close(done)
+	wg.Wait()
+	if l := src.Len() + mid.Len() + dst.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test that Pipe returns when done is closed while the destination is full.
+func TestPipeDoneWhileBlocked(t *testing.T) {
+	src := New[int](4)
+	dst := New[int](1)
+	done := make(chan struct{})
+	returned := make(chan struct{})
+
+	for i := 1; i <= 3; i++ {
+		src.Push(i)
+	}
+	go func() {
+		Pipe(src, dst, func(v int) int { return v * 10 }, done)
+		// The element being piped when done was closed must remain at the front of the source.
+		// Pipe's goroutine remains the consumer of the source, so it checks the element itself.
+		if l := src.Len(); l != 2 {
+			t.Errorf("Unexpected length; %v != 2", l)
+		}
+		if v := src.Pop(); v != 2 {
+			t.Errorf("Got incorrect value; %v != 2", v)
+		}
+		close(returned)
+	}()
+
+	for src.Len() != 2 {
+		runtime.Gosched()
+	}
+	close(done)
+	<-returned
+
+	if v := dst.Pop(); v != 10 {
+		t.Errorf("Got incorrect value; %v != 10", v)
+	}
+}
@@ -0,0 +1,52 @@
+package spscqueue
+
+// Pool is a fixed-size object pool built on a queue of idle objects: getting an object removes it
+// from the queue, and putting it back adds it again, so objects are recycled in the order in which
+// they were returned. Following from the queue, at most one goroutine may get objects from the
+// pool (the borrower), and at most one goroutine may put them back (the returner); the two may
+// differ, and usually do, e.g. if a consumer returns the buffers a producer filled and handed it.
+// The pool never allocates after construction. Only objects obtained from the pool may be put
+// back, each at most once per Get; the pool does not track which objects are borrowed.
+type Pool[T any] struct {
+	q *Queue[T]
+}
+
+// NewPool[T any] returns a pool holding `size` objects of type `T`, generated using `gen`. `size`
+// is also the largest number of objects the pool can hold.
+func NewPool[T any](size uint, gen func() T) *Pool[T] {
+	objs := make([]T, size)
+	for i := range objs {
+		objs[i] = gen()
+	}
+	q, _ := NewSeeded[T](size, objs)
+
+	return &Pool[T]{q}
+}
+
+// Get takes an idle object from the pool. Get returns the object and true, or the zero-value for
+// the type and false if all objects are borrowed. Get does not block.
+// Get should be called by the borrower.
+func (p *Pool[T]) Get() (T, bool) {
+	obj, ok := p.q.Front()
+	if ok {
+		p.q.Advance()
+	}
+
+	return obj, ok
+}
+
+// Put returns an object to the pool. Put panics if the pool already holds all of its objects, since
+// more objects were returned than borrowed.
+// Put should be called by the returner.
+func (p *Pool[T]) Put(obj T) {
+	if !p.q.Offer(obj) {
+		panic("spscqueue: putting more objects into the pool than were taken from it")
+	}
+}
+
+// Idle returns the number of objects in the pool which are not borrowed. As with Queue.Len, the
+// result is only a snapshot.
+// Any thread may call Idle.
+func (p *Pool[T]) Idle() uint64 {
+	return p.q.Len()
+}
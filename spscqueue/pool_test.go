@@ -0,0 +1,98 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// Test for borrowing and returning all objects of a pool.
+func TestPool(t *testing.T) {
+	const size = 4
+	var generated int
+	p := NewPool[*int](size, func() *int {
+		generated++
+		v := generated
+		return &v
+	})
+	if generated != size {
+		t.Errorf("Got incorrect number of objects generated; %v != %v", generated, size)
+	}
+
+	var borrowed []*int
+	for i := 0; i < size; i++ {
+		obj, ok := p.Get()
+		if !ok || *obj != i+1 {
+			t.Fatalf("Got incorrect object; %v, %v", obj, ok)
+		}
+		borrowed = append(borrowed, obj)
+	}
+	if obj, ok := p.Get(); ok || obj != nil {
+		t.Errorf("Got object from exhausted pool; %v", obj)
+	}
+	for _, obj := range borrowed {
+		p.Put(obj)
+	}
+	if n := p.Idle(); n != size {
+		t.Errorf("Unexpected number of idle objects; %v != %v", n, size)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Putting more objects than were taken did not panic")
+		}
+	}()
+	p.Put(new(int))
+}
+
+// SPSC test for cycling objects between a borrower and a returner.
+func TestPoolCycle(t *testing.T) {
+	const size = 8
+	const numCycles = 10000
+	var id int
+	p := NewPool[*int](size, func() *int {
+		id++
+		v := id
+		return &v
+	})
+	handoff := make(chan *int, size)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		defer close(handoff)
+		for i := 0; i < numCycles; i++ {
+			obj, ok := p.Get()
+			for !ok {
+				runtime.Gosched()
+				obj, ok = p.Get()
+			}
+			handoff <- obj
+		}
+	}(&wg)
+
+	seen := make(map[*int]int)
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for obj := range handoff {
+			seen[obj]++
+			p.Put(obj)
+		}
+	}(&wg)
+
+	wg.Wait()
+	if len(seen) != size {
+		t.Errorf("Unexpected number of distinct objects; %v != %v", len(seen), size)
+	}
+	if n := p.Idle(); n != size {
+		t.Errorf("Lost objects; %v idle != %v", n, size)
+	}
+	// The pool recycles in order, so every object is borrowed equally often.
+	for obj, n := range seen {
+		if n != numCycles/size {
+			t.Errorf("Object %v borrowed unevenly; %v != %v", *obj, n, numCycles/size)
+		}
+	}
+}
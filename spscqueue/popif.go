@@ -0,0 +1,17 @@
+package spscqueue
+
+// PopIfEqual removes and returns the element at the front of the queue if it is equal to
+// `expected`. A boolean indicator of success or failure is included as a second return value; if
+// the queue is empty or its front differs from `expected`, the queue is left unchanged and the
+// zero-value for the type is returned. Since only the consumer moves the front of the queue, the
+// comparison and removal need no further synchronisation. PopIfEqual does not block.
+// PopIfEqual should be called by the consumer.
+func PopIfEqual[T comparable](q *Queue[T], expected T) (T, bool) {
+	if el, ok := q.Front(); ok && el == expected {
+		q.Advance()
+		return el, true
+	}
+
+	var t T
+	return t, false
+}
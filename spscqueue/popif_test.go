@@ -0,0 +1,30 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test that PopIfEqual only removes a matching front.
+func TestPopIfEqual(t *testing.T) {
+	q := New[string](4)
+	if v, ok := PopIfEqual(q, ""); ok || v != "" {
+		t.Errorf("PopIfEqual succeeded on an empty queue; %q, %v", v, ok)
+	}
+
+	q.Push("open")
+	q.Push("close")
+	if v, ok := PopIfEqual(q, "close"); ok || v != "" {
+		t.Errorf("PopIfEqual removed a mismatching front; %q, %v", v, ok)
+	}
+	if l := q.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+	for _, want := range []string{"open", "close"} {
+		if v, ok := PopIfEqual(q, want); !ok || v != want {
+			t.Errorf("Got incorrect value; %q, %v != %q, true", v, ok, want)
+		}
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
@@ -0,0 +1,40 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// Push2 adds the passed element to both `a` and `b` if both have an available slot and neither has
+// been closed, and returns true. Otherwise, the element is added to neither queue and Push2 returns
+// false. Push2 checks for space in both queues before adding to either; since only their producer
+// can take up free slots, both additions are then guaranteed to succeed. The element is published
+// to `a` before `b`, so the consumers of the two queues may observe it at slightly different
+// times. Push2 does not block. `a` and `b` must be distinct queues.
+// Push2 should be called by the producer of both queues.
+func Push2[T any](a, b *Queue[T], el T) bool {
+	if !a.hasSpace() || !b.hasSpace() {
+		return false
+	}
+
+	a.Offer(el)
+	b.Offer(el)
+	return true
+}
+
+// hasSpace reports whether Offer would add an element to the queue, i.e. whether the queue has an
+// available slot and has not been closed, without adding one.
+// hasSpace should be called by the producer.
+func (q *Queue[T]) hasSpace() bool {
+	q.checkProducer()
+	if q.closed != 0 {
+		return false
+	}
+
+	wIdxNext := q.next(q.wIdx)
+	if q.collides(wIdxNext, q.rIdxCached) {
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+		return !q.collides(wIdxNext, q.rIdxCached)
+	}
+
+	return true
+}
@@ -0,0 +1,44 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test that Push2 adds the element to both queues or to neither.
+func TestPush2(t *testing.T) {
+	a, b := New[int](2), New[int](1)
+	if !Push2(a, b, 1) {
+		t.Error("Push2 failed with space in both queues")
+	}
+
+	// b is full now, so neither queue may receive the element.
+	if Push2(a, b, 2) {
+		t.Error("Push2 succeeded with a full queue")
+	}
+	if la, lb := a.Len(), b.Len(); la != 1 || lb != 1 {
+		t.Errorf("Unexpected lengths; %v, %v != 1, 1", la, lb)
+	}
+	if Push2(b, a, 2) {
+		t.Error("Push2 succeeded with a full queue")
+	}
+	if la, lb := a.Len(), b.Len(); la != 1 || lb != 1 {
+		t.Errorf("Unexpected lengths; %v, %v != 1, 1", la, lb)
+	}
+
+	b.Pop()
+	a.Close()
+	if Push2(a, b, 3) {
+		t.Error("Push2 succeeded with a closed queue")
+	}
+	if lb := b.Len(); lb != 0 {
+		t.Errorf("Unexpected length; %v != 0", lb)
+	}
+
+	a = New[int](2)
+	if !Push2(a, b, 4) {
+		t.Error("Push2 failed with space in both queues")
+	}
+	if va, vb := a.Pop(), b.Pop(); va != 4 || vb != 4 {
+		t.Errorf("Got incorrect values; %v, %v != 4, 4", va, vb)
+	}
+}
@@ -0,0 +1,39 @@
+package spscqueue
+
+import (
+	"io"
+)
+
+// ReadAllFrom reads from `r` into a byte queue until `r` returns io.EOF, and returns the number of
+// bytes read. The bytes are read directly into the free slots of the underlying buffer, one
+// contiguous span as returned by WritableSpan per call to r.Read, and are made available to the
+// consumer after each call. ReadAllFrom blocks while the queue is full. Any error returned by `r`
+// other than io.EOF is returned after committing the bytes read along with it; ReadAllFrom returns
+// ErrClosed without reading if the queue has been closed.
+// ReadAllFrom should be called by the producer.
+func ReadAllFrom(q *Queue[byte], r io.Reader) (int, error) {
+	if q.closed != 0 {
+		return 0, ErrClosed
+	}
+
+	var read int
+	for {
+		span := q.WritableSpan()
+		if len(span) == 0 {
+			q.WaitForSpace(1)
+			continue
+		}
+		// A read filling the span exactly leaves the back of the queue at the end of the
+		// underlying buffer, so the next span starts over at its first slot.
+		n, err := r.Read(span)
+		if n > 0 {
+			q.CommitN(uint64(n))
+			read += n
+		}
+		if err == io.EOF {
+			return read, nil
+		} else if err != nil {
+			return read, err
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package spscqueue
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+// SPSC test for reading a payload from a reader through a small queue.
+func TestReadAllFrom(t *testing.T) {
+	payload := make([]byte, 5000)
+	for i := range payload {
+		payload[i] = byte(i * 7)
+	}
+	q := New[byte](13)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		n, err := ReadAllFrom(q, bytes.NewReader(payload))
+		if n != len(payload) || err != nil {
+			t.Errorf("Unexpected result; %v, %v != %v, nil", n, err, len(payload))
+		}
+	}(&wg)
+
+	got := make([]byte, 0, len(payload))
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for len(got) < len(payload) {
+			got = append(got, q.Pop())
+		}
+	}(&wg)
+
+	wg.Wait()
+	if !bytes.Equal(got, payload) {
+		t.Errorf("Got incorrect value; %v bytes != %v bytes", len(got), len(payload))
+	}
+}
+
+// Test that reader errors and closed queues are reported.
+func TestReadAllFromError(t *testing.T) {
+	q := New[byte](8)
+	r := iotest.TimeoutReader(bytes.NewReader([]byte("abcdef")))
+	if n, err := ReadAllFrom(q, r); n != 6 || !errors.Is(err, iotest.ErrTimeout) {
+		t.Errorf("Unexpected result; %v, %v != 6, %v", n, err, iotest.ErrTimeout)
+	}
+	if l := q.Len(); l != 6 {
+		t.Errorf("Unexpected length; %v != 6", l)
+	}
+
+	q.Close()
+	if n, err := ReadAllFrom(q, bytes.NewReader([]byte("g"))); n != 0 || !errors.Is(err, ErrClosed) {
+		t.Errorf("Unexpected result; %v, %v != 0, %v", n, err, ErrClosed)
+	}
+}
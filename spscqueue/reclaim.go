@@ -0,0 +1,40 @@
+package spscqueue
+
+// WithReclaim registers a function which is passed the previous occupant of a slot right before the
+// producer reuses the slot, e.g. to return pooled elements to a free list. Each committed element
+// is passed to `reclaim` exactly once, on the producer's goroutine, when its slot is next handed to
+// the producer for writing: by Push, Offer, PushSlice, Reserve, ReserveWait, ReserveSlot, ReserveN
+// and WritableSpan. Reserve and ReserveWait still return the reclaimed element, which the producer
+// may then reuse in place.
+// Since slots are only reused once the consumer has moved past them, an element is never reclaimed
+// while it is still in the queue. It may however be reclaimed while the consumer is still using a
+// copy obtained through Pop; a consumer which has to finish with an element before it is recycled
+// should use Front and Advance instead.
+// The type accepted by `reclaim` must be the element type of the queue; New panics otherwise.
+func WithReclaim[T any](reclaim func(T)) Option {
+	return func(o *options) {
+		o.reclaim = reclaim
+	}
+}
+
+// markOccupied records that the slots from index `from` up to index `to` hold committed elements,
+// which are to be reclaimed when the slots are reused.
+// markOccupied should be called by the producer.
+func (q *Queue[T]) markOccupied(from, to uint64) {
+	for idx := from; idx != to; idx = q.next(idx) {
+		q.occupied[q.slot(idx)] = true
+	}
+}
+
+// reclaimSlots passes the committed elements in the `n` slots starting at index `idx` to the
+// reclaim function, unless they have already been reclaimed.
+// reclaimSlots should be called by the producer.
+func (q *Queue[T]) reclaimSlots(idx, n uint64) {
+	for i := uint64(0); i < n; i++ {
+		slot := q.slot(q.add(idx, i))
+		if q.occupied[slot] {
+			q.occupied[slot] = false
+			q.reclaim(q.items[slot])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test that each committed element is reclaimed exactly once, after the consumer has moved past it.
+func TestReclaim(t *testing.T) {
+	const numItems = 20
+	reclaimed := map[*int]int{}
+	var popped []*int
+	q := New[*int](3, WithReclaim(func(p *int) {
+		reclaimed[p]++
+		var seen bool
+		for _, v := range popped {
+			seen = seen || v == p
+		}
+		if !seen {
+			t.Errorf("Reclaimed element %v before it was consumed", *p)
+		}
+	}))
+
+	// Cycle through the slots several times, using each of the methods which reclaim slots.
+	els := make([]*int, numItems)
+	for i := range els {
+		v := i
+		els[i] = &v
+	}
+	for i := 0; i < numItems; i++ {
+		switch i % 6 {
+		case 0:
+			q.Push(els[i])
+		case 1:
+			q.Offer(els[i])
+		case 2:
+			p, seq, _ := q.ReserveSlot()
+			*p = els[i]
+			q.CommitUpTo(seq)
+		case 3:
+			// Reserving the span twice must not reclaim its slots twice.
+			q.WritableSpan()
+			q.ReserveN(1)[0] = els[i]
+			q.CommitN(1)
+		case 4:
+			// Reserving the slot twice must not reclaim it twice.
+			q.Reserve()
+			if _, ok := q.Reserve(); !ok {
+				t.Error("Failed to reserve slot in non-full queue")
+			}
+			*q.ReserveWait() = els[i]
+			q.Commit()
+		case 5:
+			*q.ReserveWait() = els[i]
+			q.Commit()
+		}
+		popped = append(popped, q.Pop())
+	}
+
+	// Only the elements last written to each slot have not been reclaimed yet.
+	if len(reclaimed) != numItems-len(q.items) {
+		t.Errorf("Unexpected number of reclaimed elements; %v != %v", len(reclaimed),
+			numItems-len(q.items))
+	}
+	for p, n := range reclaimed {
+		if n != 1 {
+			t.Errorf("Element %v reclaimed %v times", *p, n)
+		}
+	}
+
+	if rest := q.PushSlice(els[:2]); rest != nil {
+		t.Errorf("Unexpected leftover elements; %v", len(rest))
+	}
+	if len(reclaimed) != numItems-len(q.items)+2 {
+		t.Errorf("Unexpected number of reclaimed elements; %v != %v", len(reclaimed),
+			numItems-len(q.items)+2)
+	}
+}
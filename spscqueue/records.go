@@ -0,0 +1,160 @@
+package spscqueue
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// RecordView presents a byte queue as a queue of fixed-size records of type `T`, which are moved
+// through the underlying buffer by reinterpreting its bytes in place rather than by encoding and
+// decoding them. A record occupies unsafe.Sizeof(T) bytes of the queue in the in-memory
+// representation of `T`, including any padding, and is published to the consumer and removed from
+// the queue as a whole.
+//
+// RecordView is inherently unsafe, and is only suitable for moving records between goroutines of
+// the same process. The byte representation depends on the architecture, e.g. its endianness and
+// alignment rules, so bytes written through a RecordView must not be interpreted elsewhere; the
+// padding bytes of a record are not guaranteed to have any particular value. AsRecords guards
+// against the preconditions it can check, but the following remain the caller's responsibility:
+//   - The producer and the consumer must only add and remove records through views of the same
+//     record type, since mixing in other byte operations moves the indices off record boundaries.
+//   - Resize and the other methods which replace the underlying buffer invalidate the view.
+//   - The pointers returned by Front are only valid until the consumer calls Advance.
+type RecordView[T any] struct {
+	q    *Queue[byte]
+	size uint64
+}
+
+// AsRecords[T any] returns a view of the byte queue `q` as a queue of records of type `T`; see
+// RecordView for the caveats. `T` must consist of numbers only, i.e. integers, floating-point and
+// complex numbers and arrays and structs thereof, such that the garbage collector need not track
+// it and any byte pattern is a valid value. The underlying buffer of Cap()+1 bytes must hold a
+// whole number of records and must be aligned for `T`, and both indices of `q` must be at a record
+// boundary, e.g. because `q` is empty and has not been used yet. AsRecords panics if any of these
+// conditions is not met. The number of records the view can hold is one less than the number of
+// records the underlying buffer holds, since the queue always keeps one byte free.
+// AsRecords may only be called while neither the producer nor the consumer is operating on `q`.
+func AsRecords[T any](q *Queue[byte]) *RecordView[T] {
+	var zero T
+	size := uint64(unsafe.Sizeof(zero))
+	switch {
+	case size == 0:
+		panic(fmt.Sprintf("spscqueue: cannot view queue as records of zero-sized %T", zero))
+	case !numeric(reflect.TypeOf(zero)):
+		panic(fmt.Sprintf("spscqueue: cannot view queue as records of %T, which does not "+
+			"consist of numbers only", zero))
+	case uint64(len(q.items))%size != 0:
+		panic(fmt.Sprintf("spscqueue: buffer of %v bytes does not hold a whole number of "+
+			"%v-byte records of %T", len(q.items), size, zero))
+	case uintptr(unsafe.Pointer(&q.items[0]))%unsafe.Alignof(zero) != 0:
+		panic(fmt.Sprintf("spscqueue: buffer is not aligned to the %v-byte alignment of %T",
+			unsafe.Alignof(zero), zero))
+	case q.slot(q.rIdx)%size != 0 || q.slot(q.wIdx)%size != 0:
+		panic(fmt.Sprintf("spscqueue: indices %v and %v are not at boundaries of %v-byte "+
+			"records", q.rIdx, q.wIdx, size))
+	}
+
+	return &RecordView[T]{q, size}
+}
+
+// numeric reports whether values of type `t` consist only of numbers, such that any byte pattern
+// is a valid value.
+func numeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return numeric(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !numeric(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// Cap returns the number of records the view can hold.
+// Any thread may call Cap.
+func (v *RecordView[T]) Cap() uint64 {
+	return uint64(len(v.q.items))/v.size - 1
+}
+
+// Len returns the number of records in the queue. As with Queue.Len, the result is only a
+// snapshot.
+// Any thread may call Len.
+func (v *RecordView[T]) Len() uint64 {
+	return v.q.Len() / v.size
+}
+
+// Offer adds `rec` to the queue if there is room for it and the queue has not been closed, and
+// returns whether it was added.
+// Offer should be called by the producer.
+func (v *RecordView[T]) Offer(rec T) bool {
+	if v.q.closed != 0 {
+		return false
+	}
+
+	return v.put(rec)
+}
+
+// Push adds `rec` to the queue, blocking until there is room for it.
+// Push should be called by the producer.
+func (v *RecordView[T]) Push(rec T) {
+	v.q.WaitForSpace(v.size)
+	v.put(rec)
+}
+
+// put copies `rec` into the free bytes at the back of the queue and publishes it, if they fit it.
+// Since the buffer holds a whole number of records and the write index is at a record boundary,
+// the free bytes up to the end of the buffer either fit the record or are followed by the consumer.
+func (v *RecordView[T]) put(rec T) bool {
+	span := v.q.WritableSpan()
+	if uint64(len(span)) < v.size {
+		return false
+	}
+	*(*T)(unsafe.Pointer(&span[0])) = rec
+	v.q.CommitN(v.size)
+
+	return true
+}
+
+// Front returns a pointer to the oldest record in the queue, which refers to the underlying
+// buffer, and true; if the queue is empty, Front returns nil and false. The record remains in the
+// queue until the consumer calls Advance, which invalidates the pointer.
+// Front should be called by the consumer.
+func (v *RecordView[T]) Front() (*T, bool) {
+	span := v.q.ReadableSpan()
+	if uint64(len(span)) < v.size {
+		return nil, false
+	}
+
+	return (*T)(unsafe.Pointer(&span[0])), true
+}
+
+// Advance removes the oldest record from the queue. Advance panics if the queue is empty.
+// Advance should be called by the consumer.
+func (v *RecordView[T]) Advance() {
+	v.q.AdvanceN(v.size)
+}
+
+// Poll removes the oldest record from the queue and returns a copy of it, and true; if the queue
+// is empty, Poll returns the zero-value for the type and false.
+// Poll should be called by the consumer.
+func (v *RecordView[T]) Poll() (T, bool) {
+	p, ok := v.Front()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	rec := *p
+	v.Advance()
+
+	return rec, true
+}
@@ -0,0 +1,87 @@
+package spscqueue
+
+import (
+	"bytes"
+	"testing"
+	"unsafe"
+)
+
+// record is a fixed-size record with padding between its fields.
+type record struct {
+	A uint32
+	B uint16
+	C [2]int8
+	D float64
+	E uint8
+}
+
+// Test for round-tripping records through a byte queue.
+func TestRecordView(t *testing.T) {
+	const size = unsafe.Sizeof(record{})
+	q := New[byte](uint(4*size - 1))
+	v := AsRecords[record](q)
+	if c := v.Cap(); c != 3 {
+		t.Errorf("Got incorrect capacity; %v != 3", c)
+	}
+
+	// Wrap around the underlying buffer a few times.
+	for i := 0; i < 10; i++ {
+		rec := record{uint32(i), uint16(i * 3), [2]int8{int8(-i), 1}, float64(i) / 4, uint8(i)}
+		v.Push(rec)
+		if !v.Offer(rec) {
+			t.Fatal("Failed to add record")
+		}
+		if l := v.Len(); l != 2 {
+			t.Errorf("Unexpected length; %v != 2", l)
+		}
+
+		// The record is stored in its in-memory representation.
+		span := q.ReadableSpan()
+		want := unsafe.Slice((*byte)(unsafe.Pointer(&rec)), size)
+		if uint64(len(span)) < uint64(size) || !bytes.Equal(span[:size], want) {
+			t.Errorf("Got incorrect bytes; %v != %v", span, want)
+		}
+
+		if p, ok := v.Front(); !ok || *p != rec {
+			t.Errorf("Got incorrect value; %v != %v", p, rec)
+		}
+		v.Advance()
+		if got, ok := v.Poll(); !ok || got != rec {
+			t.Errorf("Got incorrect value; %v != %v", got, rec)
+		}
+	}
+	if _, ok := v.Poll(); ok {
+		t.Error("Got record from empty queue")
+	}
+
+	for i := 0; i < 3; i++ {
+		v.Push(record{})
+	}
+	if v.Offer(record{}) {
+		t.Error("Added record to full queue")
+	}
+}
+
+// Test that views violating the preconditions are rejected.
+func TestRecordViewInvalid(t *testing.T) {
+	const size = uint(unsafe.Sizeof(record{}))
+	shifted := New[byte](4*size - 1)
+	shifted.Push(0)
+	aligned := make([]uint32, 3)
+	misaligned := unsafe.Slice((*byte)(unsafe.Pointer(&aligned[0])), 12)[1:9]
+
+	for _, tc := range []struct {
+		substr string
+		f      func()
+	}{
+		{"zero-sized", func() { AsRecords[struct{}](New[byte](7)) }},
+		{"numbers only", func() { AsRecords[*int](New[byte](7)) }},
+		{"numbers only", func() { AsRecords[bool](New[byte](7)) }},
+		{"numbers only", func() { AsRecords[struct{ s string }](New[byte](31)) }},
+		{"whole number", func() { AsRecords[record](New[byte](4 * size)) }},
+		{"not aligned", func() { AsRecords[uint32](&Queue[byte]{items: misaligned}) }},
+		{"boundaries", func() { AsRecords[record](shifted) }},
+	} {
+		expectPanic(t, tc.substr, tc.f)
+	}
+}
@@ -0,0 +1,80 @@
+package spscqueue
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// ReserveSlot reserves the next open slot at the back of the queue and returns a pointer to it,
+// along with the slot's sequence number. Contrary to Reserve, subsequent calls to ReserveSlot
+// return successive slots, so that several slots may be filled concurrently or out of order. The
+// filled slots are made available to the consumer using CommitUpTo. ReserveSlot returns false if
+// no slot is available. Sequence numbers increase by one per reserved slot.
+// Other producer methods must not be used while slots reserved through ReserveSlot remain
+// uncommitted, since they operate on the same slots.
+// ReserveSlot should be called by the producer.
+func (q *Queue[T]) ReserveSlot() (*T, uint64, bool) {
+	q.checkProducer()
+	idx := q.add(q.wIdx, q.resSeq-q.pubSeq)
+
+	// Check if we ran into the consumer.
+	idxNext := q.next(idx)
+	if q.collides(idxNext, q.rIdxCached) {
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+		if q.collides(idxNext, q.rIdxCached) {
+			return nil, 0, false
+		}
+	}
+
+	if q.completed == nil {
+		q.completed = make([]bool, len(q.items))
+	}
+	if q.reclaim != nil {
+		q.reclaimSlots(idx, 1)
+	}
+	seq := q.resSeq
+	q.resSeq++
+
+	return &q.items[q.slot(idx)], seq, true
+}
+
+// CommitUpTo marks the slot with sequence number `seq`, as returned by ReserveSlot, as filled. It
+// then makes all filled slots available to the consumer up to, but excluding, the oldest slot which
+// has not been marked yet. Elements are thus always presented to the consumer in the order in which
+// their slots were reserved, regardless of the order in which they are committed. CommitUpTo panics
+// if `seq` does not refer to an outstanding reservation.
+// CommitUpTo should be called by the producer.
+func (q *Queue[T]) CommitUpTo(seq uint64) {
+	q.checkProducer()
+	if seq-q.pubSeq >= q.resSeq-q.pubSeq {
+		panic(fmt.Sprintf("spscqueue: committing sequence %v which is not reserved", seq))
+	}
+	q.completed[q.slot(q.add(q.wIdx, seq-q.pubSeq))] = true
+
+	wIdx := q.wIdx
+	for q.pubSeq != q.resSeq && q.completed[q.slot(wIdx)] {
+		q.completed[q.slot(wIdx)] = false
+		wIdx = q.next(wIdx)
+		q.pubSeq++
+	}
+	if wIdx == q.wIdx {
+		return
+	}
+
+	q.publish(wIdx)
+}
+
+// AbortReservation discards all outstanding reservations made through ReserveN, WritableSpan or
+// ReserveSlot without publishing them; this includes slots reserved through ReserveSlot which have
+// already been marked as filled by CommitUpTo but are still waiting on an older slot. The consumer
+// never observes elements written to discarded slots. Subsequent reservations start afresh at the
+// back of the queue and overwrite the discarded slots, and ReserveSlot hands out their sequence
+// numbers again.
+// AbortReservation should be called by the producer.
+func (q *Queue[T]) AbortReservation() {
+	q.checkProducer()
+	for seq := q.pubSeq; seq != q.resSeq; seq++ {
+		q.completed[q.slot(q.add(q.wIdx, seq-q.pubSeq))] = false
+	}
+	q.resSeq = q.pubSeq
+}
@@ -0,0 +1,97 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for filling reserved slots out of order.
+func TestReserveSlotCommitUpTo(t *testing.T) {
+	q := New[int](4)
+
+	// Cycle through the underlying buffer a few times.
+	for round := 0; round < 3; round++ {
+		var slots [4]*int
+		var seqs [4]uint64
+		for i := range slots {
+			p, seq, ok := q.ReserveSlot()
+			if !ok {
+				t.Fatalf("Failed to reserve slot %v", i)
+			}
+			if i > 0 && seq != seqs[i-1]+1 {
+				t.Errorf("Unexpected sequence number; %v != %v", seq, seqs[i-1]+1)
+			}
+			slots[i], seqs[i] = p, seq
+		}
+		if _, _, ok := q.ReserveSlot(); ok {
+			t.Error("Managed to reserve slot in full queue!")
+		}
+
+		for _, c := range []struct {
+			slot   int
+			length uint64
+		}{{2, 0}, {0, 1}, {3, 1}, {1, 4}} {
+			*slots[c.slot] = round*10 + c.slot
+			q.CommitUpTo(seqs[c.slot])
+			if l := q.Len(); l != c.length {
+				t.Errorf("Unexpected length after committing slot %v; %v != %v", c.slot, l, c.length)
+			}
+		}
+
+		for i := 0; i < 4; i++ {
+			if v := q.Pop(); v != round*10+i {
+				t.Errorf("Got incorrect value; %v != %v", v, round*10+i)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Committing an unreserved sequence did not panic")
+		}
+	}()
+	q.CommitUpTo(100)
+}
+
+// Test that aborted reservations are never presented to the consumer.
+func TestAbortReservation(t *testing.T) {
+	q := New[int](4)
+
+	span := q.ReserveN(3)
+	for i := range span {
+		span[i] = i + 1
+	}
+	q.AbortReservation()
+	if v, ok := q.Front(); ok {
+		t.Errorf("Consumer observed aborted element %v", v)
+	}
+
+	// Fill the second of two reserved slots, such that it waits on the first.
+	q.ReserveSlot()
+	p, seq, _ := q.ReserveSlot()
+	*p = 10
+	q.CommitUpTo(seq)
+	q.AbortReservation()
+	if v, ok := q.Front(); ok {
+		t.Errorf("Consumer observed aborted element %v", v)
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+
+	// A fresh reservation starts at the back of the queue, and must not publish the discarded slot.
+	if span = q.ReserveN(4); len(span) != 4 {
+		t.Errorf("Unexpected span length; %v != 4", len(span))
+	}
+	p, next, _ := q.ReserveSlot()
+	if next != seq-1 {
+		t.Errorf("Unexpected sequence number; %v != %v", next, seq-1)
+	}
+	*p = 20
+	q.CommitUpTo(next)
+	if l := q.Len(); l != 1 {
+		t.Errorf("Unexpected length; %v != 1", l)
+	}
+	if v := q.Pop(); v != 20 {
+		t.Errorf("Got incorrect value; %v != 20", v)
+	}
+}
@@ -0,0 +1,138 @@
+package spscqueue
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Resize changes the capacity of the queue to `size`, retaining the elements it contains. The
+// elements are moved to the start of a newly allocated underlying buffer. Resize returns an error
+// if the queue holds more than `size` elements.
+// Resize may only be called while neither the producer nor the consumer is operating on the queue,
+// and while no slots reserved through ReserveSlot are outstanding.
+func (q *Queue[T]) Resize(size uint) error {
+	n := q.length(q.rIdx, q.wIdx)
+	if n > uint64(size) {
+		return fmt.Errorf("spscqueue: resizing queue holding %v elements to capacity %v", n, size)
+	}
+
+	items := make([]T, size+1)
+	for i, idx := 0, q.rIdx; idx != q.wIdx; i, idx = i+1, q.next(idx) {
+		items[i] = q.items[q.slot(idx)]
+	}
+	if q.dwell != nil {
+		dwell := make([]int64, len(items))
+		for i, idx := 0, q.rIdx; idx != q.wIdx; i, idx = i+1, q.next(idx) {
+			dwell[i] = q.dwell[q.slot(idx)]
+		}
+		q.dwell = dwell
+	}
+	q.items = items
+	q.updateIndexing()
+
+	if q.filled != nil {
+		// The retained elements were generated before, so they are reused like any other slot.
+		q.filled = make([]bool, len(q.items))
+		for i := uint64(0); i < n; i++ {
+			q.filled[i] = true
+		}
+	}
+	if q.occupied != nil {
+		q.occupied = make([]bool, len(q.items))
+		for i := uint64(0); i < n; i++ {
+			q.occupied[i] = true
+		}
+	}
+	if q.completed != nil {
+		q.completed = make([]bool, len(q.items))
+	}
+
+	q.setIndices(0, n)
+	q.pubSeq = q.resSeq
+
+	return nil
+}
+
+// ResetAndFill empties the queue and reopens it if it was closed, keeping its capacity and
+// underlying buffer, and then initialises every slot using `gen` like Fill. This prepares a queue
+// of pre-allocated elements for reuse with the Reserve-Commit pattern without reallocating it,
+// e.g. when recycling queues between request lifecycles. Elements still in the queue are
+// discarded, as are outstanding reservations. The statistics of the queue are retained.
+// ResetAndFill may only be called while neither the producer nor the consumer is operating on the
+// queue.
+func (q *Queue[T]) ResetAndFill(gen func() T) {
+	q.Fill(gen)
+	for i := range q.filled {
+		q.filled[i] = true
+	}
+	for i := range q.completed {
+		q.completed[i] = false
+	}
+	for i := range q.occupied {
+		q.occupied[i] = false
+	}
+
+	q.setIndices(0, 0)
+	q.pubSeq = q.resSeq
+	atomic.StoreUint32(&q.closed, 0)
+}
+
+const (
+	// autoSizeInitial is the initial capacity of queues created using NewAutoSized.
+	autoSizeInitial = 16
+	// autoSizeMax is the capacity beyond which queues created using NewAutoSized do not grow.
+	autoSizeMax = 1 << 20
+)
+
+// autoSizeConfig holds the configuration of queues created using NewAutoSized.
+type autoSizeConfig struct {
+	targetMaxStall int64
+}
+
+// NewAutoSized returns an empty queue which can grow its capacity in response to producer stalls.
+// The queue starts out with a small capacity and tracks the longest time the producer had to wait
+// for the consumer in Push. Each call to AutoTune doubles the capacity if that time exceeded
+// `targetMaxStallNs` nanoseconds, up to a capacity of 1048576 elements.
+func NewAutoSized[T any](targetMaxStallNs int64, opts ...Option) *Queue[T] {
+	q := New[T](autoSizeInitial, opts...)
+	q.autoSize = &autoSizeConfig{targetMaxStall: targetMaxStallNs}
+
+	cb := q.blockCallback
+	q.blockCallback = func(d time.Duration) {
+		if int64(d) > q.maxStall {
+			atomic.StoreInt64(&q.maxStall, int64(d))
+		}
+		if cb != nil {
+			cb(d)
+		}
+	}
+
+	return q
+}
+
+// AutoTune grows a queue created using NewAutoSized if the producer stalled for longer than the
+// target since the previous call to AutoTune, and returns whether the queue was grown. AutoTune
+// resets the recorded stall time.
+// Growing the queue reallocates its underlying buffer, so AutoTune is subject to the same
+// restrictions as Resize: it may only be called while neither the producer nor the consumer is
+// operating on the queue. A typical protocol is for a maintenance goroutine to periodically ask the
+// producer and the consumer to pause (e.g. through a pair of channels), wait for both to
+// acknowledge that they have left all queue methods, call AutoTune, and then release both.
+func (q *Queue[T]) AutoTune() bool {
+	if q.autoSize == nil {
+		return false
+	}
+
+	stall := atomic.SwapInt64(&q.maxStall, 0)
+	if stall <= q.autoSize.targetMaxStall || q.Cap() >= autoSizeMax {
+		return false
+	}
+
+	size := 2 * q.Cap()
+	if size > autoSizeMax {
+		size = autoSizeMax
+	}
+
+	return q.Resize(uint(size)) == nil
+}
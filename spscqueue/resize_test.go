@@ -0,0 +1,133 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for resizing a queue holding wrapped elements.
+func TestResize(t *testing.T) {
+	q := New[int](4)
+	for i := 0; i < 3; i++ {
+		q.Push(-1)
+		q.Pop()
+	}
+	for i := 0; i < 4; i++ {
+		q.Push(i)
+	}
+
+	if err := q.Resize(3); err == nil {
+		t.Error("Resized queue below its length without error")
+	}
+	if err := q.Resize(8); err != nil {
+		t.Fatalf("Failed to resize queue; %v", err)
+	}
+	if c := q.Cap(); c != 8 {
+		t.Errorf("Unexpected capacity; %v != 8", c)
+	}
+	for i := 4; i < 8; i++ {
+		q.Push(i)
+	}
+	if q.Offer(8) {
+		t.Error("Managed to add element to full queue!")
+	}
+	for i := 0; i < 8; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+}
+
+// Test that an auto-sized queue grows under sustained backpressure.
+func TestAutoSized(t *testing.T) {
+	const batch = 200
+	q := NewAutoSized[int](int64(10 * time.Microsecond))
+	if q.AutoTune() {
+		t.Error("Queue grew without backpressure")
+	}
+
+	// The consumer stays on a single goroutine for all rounds, and pauses after each batch so that
+	// the queue can be tuned while neither side is operating on it.
+	const rounds = 3
+	done, resume := make(chan struct{}), make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < rounds*batch; i++ {
+			time.Sleep(20 * time.Microsecond)
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+			if (i+1)%batch == 0 {
+				done <- struct{}{}
+				<-resume
+			}
+		}
+	}(&wg)
+
+	next := 0
+	for round := 0; round < rounds; round++ {
+		capBefore := q.Cap()
+		for i := 0; i < batch; i++ {
+			q.Push(next)
+			next++
+		}
+		<-done
+
+		if !q.AutoTune() {
+			t.Errorf("Queue did not grow under backpressure in round %v", round)
+		}
+		if c := q.Cap(); c != 2*capBefore {
+			t.Errorf("Unexpected capacity; %v != %v", c, 2*capBefore)
+		}
+		resume <- struct{}{}
+	}
+	wg.Wait()
+}
+
+// Test for recycling a queue of pre-allocated elements.
+func TestResetAndFill(t *testing.T) {
+	var generation int
+	gen := func() *int { v := generation; return &v }
+	q := New[*int](4)
+	q.Fill(gen)
+
+	for i := 0; i < 3; i++ {
+		v, _ := q.Reserve()
+		*v = 10 + i
+		q.Commit()
+	}
+	q.Pop()
+	q.ReserveSlot()
+	q.Close()
+
+	generation = 1
+	q.ResetAndFill(gen)
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+	if c := q.Cap(); c != 4 {
+		t.Errorf("Unexpected capacity; %v != 4", c)
+	}
+	if q.Closed() {
+		t.Error("Queue still closed after reset")
+	}
+
+	seen := map[*int]bool{}
+	for i := 0; i < 4; i++ {
+		v, ok := q.Reserve()
+		if !ok {
+			t.Fatalf("Failed to reserve slot %v after reset", i)
+		}
+		if *v != 1 || seen[v] {
+			t.Errorf("Reserve returned stale slot; %v", *v)
+		}
+		seen[v] = true
+		q.Commit()
+	}
+	if q.Offer(nil) {
+		t.Error("Managed to add element to full queue!")
+	}
+}
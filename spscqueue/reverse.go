@@ -0,0 +1,18 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+)
+
+// ForEachReverse passes the elements in the queue to `f`, newest first, without removing them. The
+// elements are those available when ForEachReverse is called; elements added while it runs are not
+// visited. ForEachReverse does not block.
+// ForEachReverse should be called by the consumer.
+func (q *Queue[T]) ForEachReverse(f func(T)) {
+	q.checkConsumer()
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	for idx := q.wIdxCached; idx != q.rIdx; {
+		idx = q.prev(idx)
+		f(q.items[q.slot(idx)])
+	}
+}
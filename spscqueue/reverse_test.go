@@ -0,0 +1,40 @@
+package spscqueue
+
+import (
+	"testing"
+)
+
+// Test for iterating the elements newest first, including across the end of the underlying buffer.
+func TestForEachReverse(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithMonotonicIndices()}} {
+		q := New[int](5, opts...)
+		q.ForEachReverse(func(v int) {
+			t.Errorf("Visited element of an empty queue; %v", v)
+		})
+
+		// Move the front of the queue close to the end of the underlying buffer.
+		for i := 0; i < 4; i++ {
+			q.Push(-1)
+			q.Pop()
+		}
+		for i := 0; i < 5; i++ {
+			q.Push(i)
+		}
+
+		var got []int
+		q.ForEachReverse(func(v int) {
+			got = append(got, v)
+		})
+		if len(got) != 5 {
+			t.Fatalf("Unexpected number of visited elements; %v != 5", len(got))
+		}
+		for i, v := range got {
+			if v != 4-i {
+				t.Errorf("Got incorrect value; %v != %v", v, 4-i)
+			}
+		}
+		if l := q.Len(); l != 5 {
+			t.Errorf("Unexpected length; %v != 5", l)
+		}
+	}
+}
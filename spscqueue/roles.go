@@ -0,0 +1,62 @@
+package spscqueue
+
+// Producer is a handle on a queue which only exposes the operations of the producer. Handing the
+// producer goroutine a Producer rather than the Queue itself turns the use of consumer operations
+// from that goroutine into a compile error.
+type Producer[T any] struct {
+	q *Queue[T]
+}
+
+// Consumer is a handle on a queue which only exposes the operations of the consumer. Handing the
+// consumer goroutine a Consumer rather than the Queue itself turns the use of producer operations
+// from that goroutine into a compile error.
+type Consumer[T any] struct {
+	q *Queue[T]
+}
+
+// Producer returns a handle on the queue which only exposes the operations of the producer.
+// Any thread may call Producer.
+func (q *Queue[T]) Producer() *Producer[T] {
+	return &Producer[T]{q}
+}
+
+// Consumer returns a handle on the queue which only exposes the operations of the consumer.
+// Any thread may call Consumer.
+func (q *Queue[T]) Consumer() *Consumer[T] {
+	return &Consumer[T]{q}
+}
+
+// Push is equivalent to Queue.Push.
+func (p *Producer[T]) Push(el T) {
+	p.q.Push(el)
+}
+
+// Offer is equivalent to Queue.Offer.
+func (p *Producer[T]) Offer(el T) bool {
+	return p.q.Offer(el)
+}
+
+// Reserve is equivalent to Queue.Reserve.
+func (p *Producer[T]) Reserve() (T, bool) {
+	return p.q.Reserve()
+}
+
+// Commit is equivalent to Queue.Commit.
+func (p *Producer[T]) Commit() {
+	p.q.Commit()
+}
+
+// Pop is equivalent to Queue.Pop.
+func (c *Consumer[T]) Pop() T {
+	return c.q.Pop()
+}
+
+// Front is equivalent to Queue.Front.
+func (c *Consumer[T]) Front() (T, bool) {
+	return c.q.Front()
+}
+
+// Advance is equivalent to Queue.Advance.
+func (c *Consumer[T]) Advance() {
+	c.q.Advance()
+}
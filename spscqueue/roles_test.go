@@ -0,0 +1,96 @@
+package spscqueue
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// methodNames returns the sorted names of the methods of the type of `v`.
+func methodNames(v any) []string {
+	typ := reflect.TypeOf(v)
+	names := make([]string, typ.NumMethod())
+	for i := range names {
+		names[i] = typ.Method(i).Name
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Test that the role handles expose the operations of their role only.
+func TestRoleMethods(t *testing.T) {
+	q := New[int](4)
+	want := []string{"Commit", "Migrate", "Offer", "Push", "Reserve"}
+	if got := methodNames(q.Producer()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected producer methods; %v != %v", got, want)
+	}
+	want = []string{"Advance", "Front", "Migrate", "Pop"}
+	if got := methodNames(q.Consumer()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected consumer methods; %v != %v", got, want)
+	}
+}
+
+// SPSC test for the role handles.
+func TestRoles(t *testing.T) {
+	const numItems = 10000
+	q := New[int](64)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(p *Producer[int], wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if i%2 == 0 {
+				p.Push(i)
+				continue
+			}
+			for !p.Offer(i) {
+				runtime.Gosched()
+			}
+		}
+	}(q.Producer(), &wg)
+
+	wg.Add(1)
+	go func(c *Consumer[int], wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if i%2 == 0 {
+				if v := c.Pop(); v != i {
+					t.Errorf("Got incorrect value; %v != %v", v, i)
+				}
+				continue
+			}
+			v, ok := c.Front()
+			for !ok {
+				runtime.Gosched()
+				v, ok = c.Front()
+			}
+			if v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+			c.Advance()
+		}
+	}(q.Consumer(), &wg)
+
+	wg.Wait()
+}
+
+// Test for the Reserve-Commit pattern through the producer handle.
+func TestProducerReserveCommit(t *testing.T) {
+	q := New[*int](4)
+	q.Fill(func() *int { return new(int) })
+	p, c := q.Producer(), q.Consumer()
+
+	v, ok := p.Reserve()
+	if !ok {
+		t.Fatal("Failed to reserve element in empty queue")
+	}
+	*v = 1
+	p.Commit()
+	if v := c.Pop(); *v != 1 {
+		t.Errorf("Got incorrect value; %v != 1", *v)
+	}
+}
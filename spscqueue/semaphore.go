@@ -0,0 +1,41 @@
+package spscqueue
+
+// Semaphore is a counting semaphore built on a queue of tokens: acquiring a token removes it from
+// the queue, and releasing a token adds it back. Following from the queue, at most one goroutine
+// may acquire tokens and at most one goroutine may release them; the two may differ, and usually
+// do, e.g. to limit the number of requests a producer has in flight with a consumer.
+type Semaphore struct {
+	q *Queue[struct{}]
+}
+
+// NewSemaphore returns a semaphore holding `n` tokens, which is also the largest number of tokens
+// it can hold.
+func NewSemaphore(n uint) *Semaphore {
+	q, _ := NewSeeded[struct{}](n, make([]struct{}, n))
+	return &Semaphore{q}
+}
+
+// Acquire takes a token from the semaphore. Acquire will block if no token is available.
+// Acquire should be called by the acquirer.
+func (s *Semaphore) Acquire() {
+	s.q.Pop()
+}
+
+// TryAcquire is a non-blocking variant of Acquire. It returns true if it took a token, and false
+// if none was available.
+// TryAcquire should be called by the acquirer.
+func (s *Semaphore) TryAcquire() bool {
+	if _, ok := s.q.Front(); !ok {
+		return false
+	}
+	s.q.Advance()
+
+	return true
+}
+
+// Release returns a token to the semaphore. Releasing more tokens than were acquired is a
+// programming error; Release will block while the semaphore holds its maximum number of tokens.
+// Release should be called by the releaser.
+func (s *Semaphore) Release() {
+	s.q.Push(struct{}{})
+}
@@ -0,0 +1,64 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// Test for acquiring and releasing tokens.
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(3)
+	for i := 0; i < 3; i++ {
+		if !s.TryAcquire() {
+			t.Errorf("Failed to acquire token %v", i)
+		}
+	}
+	if s.TryAcquire() {
+		t.Error("Acquired token from exhausted semaphore")
+	}
+	s.Release()
+	if !s.TryAcquire() {
+		t.Error("Failed to acquire released token")
+	}
+	s.Release()
+	s.Release()
+	s.Acquire()
+	s.Acquire()
+	if s.TryAcquire() {
+		t.Error("Acquired token from exhausted semaphore")
+	}
+}
+
+// SPSC test limiting the number of elements in flight between two goroutines.
+func TestSemaphoreInFlight(t *testing.T) {
+	const numItems = 10000
+	const maxInFlight = 4
+	s := NewSemaphore(maxInFlight)
+	q := New[int](2 * maxInFlight)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			s.Acquire()
+			q.Push(i)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if l := q.Len(); l > maxInFlight {
+				t.Errorf("Too many elements in flight; %v > %v", l, maxInFlight)
+			}
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+			s.Release()
+		}
+	}(&wg)
+
+	wg.Wait()
+}
@@ -0,0 +1,70 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Test that LenSeq only reports possible lengths while the producer and consumer are running.
+func TestLenSeq(t *testing.T) {
+	const numItems = 100000
+	const capacity = 8
+	if l := New[int](capacity).LenSeq(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+
+	q := New[int](capacity, WithSeqLen())
+	var done uint32
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			q.Push(i)
+		}
+		atomic.StoreUint32(&done, 1)
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	var samples int
+	for atomic.LoadUint32(&done) == 0 {
+		if l := q.LenSeq(); l > capacity {
+			t.Errorf("Observed length exceeds capacity; %v > %v", l, capacity)
+		}
+		samples++
+		runtime.Gosched()
+	}
+	wg.Wait()
+	if l := q.LenSeq(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+	t.Logf("Sampled the length %v times", samples)
+}
+
+// Single-thread benchmarks comparing Push and Pop with and without the sequence counters.
+func BenchmarkPushPopSeqLen(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{{"Default", nil}, {"SeqLen", []Option{WithSeqLen()}}} {
+		b.Run(bm.name, func(b *testing.B) {
+			q := New[int](1, bm.opts...)
+			for i := 0; i < b.N; i++ {
+				q.Push(i)
+				_ = q.Pop()
+			}
+		})
+	}
+}
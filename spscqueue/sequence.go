@@ -0,0 +1,21 @@
+package spscqueue
+
+// NextSequence returns the sequence number of the next element the producer adds to the queue,
+// i.e. the number of elements added so far, including those the queue was seeded with by
+// NewSeeded. Sequence numbers increase by one per element added through any producer operation,
+// so embedding NextSequence in an element before committing it, e.g. in the slot returned by
+// ReserveWait, lets the consumer check or reconstruct the order of the elements. Moving the indices
+// through Restore, or replacing the contents of the queue, does not affect the sequence numbers.
+// For a queue with an overflow queue, the elements Push diverts to the overflow queue are counted
+// as well, so that the sequence numbers follow the order in which Pop returns the elements.
+// NextSequence should be called by the producer.
+func (q *Queue[T]) NextSequence() uint64 {
+	q.checkProducer()
+	n := q.producerCount
+	if q.overflow != nil {
+		// The overflow queue counts the elements added to it itself.
+		n += q.overflow.producerCount
+	}
+
+	return n
+}
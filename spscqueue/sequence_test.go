@@ -0,0 +1,89 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// sequenced is an element tagged with its sequence number.
+type sequenced struct {
+	seq   uint64
+	value int
+}
+
+// Test that sequence numbers increase by one per element added.
+func TestNextSequence(t *testing.T) {
+	q, err := NewSeeded[int](8, []int{0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq := q.NextSequence(); seq != 2 {
+		t.Errorf("Got incorrect value; %v != 2", seq)
+	}
+
+	q.Push(2)
+	q.Offer(3)
+	*q.ReserveWait() = 4
+	q.Commit()
+	q.PushSlice([]int{5, 6})
+	if seq := q.NextSequence(); seq != 7 {
+		t.Errorf("Got incorrect value; %v != 7", seq)
+	}
+	// Failing to add an element does not consume a sequence number.
+	q.Push(7)
+	if q.Offer(8) {
+		t.Error("Added element to full queue")
+	}
+	if seq := q.NextSequence(); seq != 8 {
+		t.Errorf("Got incorrect value; %v != 8", seq)
+	}
+}
+
+// Test that sequence numbers count the elements diverted to an overflow queue.
+func TestNextSequenceOverflow(t *testing.T) {
+	q := New[sequenced](2)
+	q.WithOverflow(New[sequenced](4))
+	for i := 0; i < 6; i++ {
+		q.Push(sequenced{q.NextSequence(), i})
+	}
+	for i := 0; i < 6; i++ {
+		if el := q.Pop(); el.seq != uint64(i) || el.value != i {
+			t.Errorf("Got incorrect value; %v != %v", el, sequenced{uint64(i), i})
+		}
+	}
+	if seq := q.NextSequence(); seq != 6 {
+		t.Errorf("Got incorrect value; %v != 6", seq)
+	}
+}
+
+// SPSC test for reconstructing the order of elements from their sequence numbers.
+func TestNextSequenceOrder(t *testing.T) {
+	const numItems = 10000
+	for _, opts := range [][]Option{nil, {WithMonotonicIndices()}, {WithStats()}} {
+		q := New[sequenced](13, opts...)
+		wg := sync.WaitGroup{}
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			for i := 0; i < numItems; i++ {
+				slot := q.ReserveWait()
+				*slot = sequenced{q.NextSequence(), i}
+				q.Commit()
+			}
+		}(&wg)
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			for i := uint64(0); i < numItems; i++ {
+				v := q.Pop()
+				if v.seq != i || v.value != int(i) {
+					t.Errorf("Got incorrect value; %v != {%v %v}", v, i, i)
+				}
+			}
+		}(&wg)
+
+		wg.Wait()
+	}
+}
@@ -0,0 +1,77 @@
+package spscqueue
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// snapshot is the serialised form of a queue.
+type snapshot[T any] struct {
+	Capacity uint64
+	Items    []T
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It encodes the capacity of the queue and the
+// elements it contains, oldest first, using encoding/gob. The elements must therefore be encodable
+// by gob.
+// MarshalBinary may only be called while neither the producer nor the consumer is operating on the
+// queue.
+func (q *Queue[T]) MarshalBinary() ([]byte, error) {
+	s := snapshot[T]{Capacity: q.Cap(), Items: make([]T, 0, q.Len())}
+	for idx := q.rIdx; idx != q.wIdx; idx = q.next(idx) {
+		s.Items = append(s.Items, q.items[q.slot(idx)])
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces the contents of the queue with
+// those encoded by MarshalBinary, resizing the queue to the encoded capacity. The restored elements
+// are placed at the start of the underlying buffer. Options the queue was created with are kept.
+// UnmarshalBinary may only be called while neither the producer nor the consumer is operating on
+// the queue, and while no slots reserved through ReserveSlot are outstanding.
+func (q *Queue[T]) UnmarshalBinary(data []byte) error {
+	var s snapshot[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&s); err != nil {
+		return err
+	}
+	if uint64(len(s.Items)) > s.Capacity {
+		return fmt.Errorf("spscqueue: snapshot holds %v elements but has capacity %v",
+			len(s.Items), s.Capacity)
+	}
+
+	q.items = make([]T, s.Capacity+1)
+	copy(q.items, s.Items)
+	if q.filled != nil {
+		q.filled = make([]bool, len(q.items))
+	}
+	if q.occupied != nil {
+		q.occupied = make([]bool, len(q.items))
+		for i := range s.Items {
+			q.occupied[i] = true
+		}
+	}
+	if q.completed != nil {
+		q.completed = make([]bool, len(q.items))
+	}
+	q.updateIndexing()
+	if q.dwell != nil {
+		// The restored elements are treated as having been added now.
+		q.dwell = make([]int64, len(q.items))
+		q.stampDwell(0, uint64(len(s.Items)))
+	}
+	if q.wait.Load() == nil {
+		q.SetWaitStrategy(defaultWaitStrategy)
+	}
+
+	q.setIndices(0, uint64(len(s.Items)))
+	q.pubSeq = q.resSeq
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package spscqueue
+
+import (
+	"encoding"
+	"strconv"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Queue[int])(nil)
+	_ encoding.BinaryUnmarshaler = (*Queue[int])(nil)
+)
+
+// Test for a MarshalBinary-UnmarshalBinary round trip.
+func TestMarshalBinary(t *testing.T) {
+	q := New[string](8)
+
+	// Wrap the contents around the end of the underlying buffer.
+	for i := 0; i < 6; i++ {
+		q.Push("")
+		q.Pop()
+	}
+	for i := 0; i < 5; i++ {
+		q.Push(strconv.Itoa(i))
+	}
+
+	data, err := q.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal queue; %v", err)
+	}
+
+	var r Queue[string]
+	if err := r.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to unmarshal queue; %v", err)
+	}
+	if c := r.Cap(); c != 8 {
+		t.Errorf("Unexpected capacity; %v != 8", c)
+	}
+	if l := r.Len(); l != 5 {
+		t.Errorf("Unexpected length; %v != 5", l)
+	}
+	for i := 0; i < 5; i++ {
+		if v := r.Pop(); v != strconv.Itoa(i) {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+
+	// The restored queue must be fully usable.
+	for i := 0; i < 8; i++ {
+		r.Push(strconv.Itoa(i))
+	}
+	if r.Offer("") {
+		t.Error("Managed to add element to full queue!")
+	}
+
+	if err := r.UnmarshalBinary([]byte("garbage")); err == nil {
+		t.Error("Unmarshalled invalid data without error")
+	}
+}
+
+// Test for using ReserveSlot and CommitUpTo on a queue which was unmarshalled into.
+func TestUnmarshalBinaryReserveSlot(t *testing.T) {
+	src := New[int](8)
+	src.Push(1)
+	data, err := src.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal queue; %v", err)
+	}
+
+	q := New[int](2)
+	p, seq, _ := q.ReserveSlot()
+	*p = 0
+	q.CommitUpTo(seq)
+	if err := q.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Failed to unmarshal queue; %v", err)
+	}
+
+	for i := 2; i < 9; i++ {
+		p, seq, ok := q.ReserveSlot()
+		if !ok {
+			t.Fatalf("Failed to reserve slot %v", i)
+		}
+		*p = i
+		q.CommitUpTo(seq)
+	}
+	for i := 1; i < 9; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package spscqueue
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// WritableSpan returns the contiguous region of free slots at the back of the queue. The producer
+// may fill any prefix of the span and make the first `n` elements available to the consumer using
+// CommitN(n). The span ends at the end of the underlying buffer or just before the slot the
+// consumer occupies, whichever comes first, so it may be shorter than the total free space when
+// the free region wraps around. An empty span is returned if the queue is full.
+// The span is invalidated by any call to Commit or CommitN.
+// WritableSpan should be called by the producer.
+func (q *Queue[T]) WritableSpan() []T {
+	q.checkProducer()
+	// Always refresh the cached read index; a stale value would needlessly shorten the span.
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+
+	start := q.slot(q.wIdx)
+	n := q.Cap() - q.length(q.rIdxCached, q.wIdx)
+	if end := uint64(len(q.items)) - start; n > end {
+		n = end
+	}
+	if q.reclaim != nil {
+		q.reclaimSlots(q.wIdx, n)
+	}
+
+	return q.items[start : start+n]
+}
+
+// ReserveN returns up to `n` contiguous free slots at the back of the queue, i.e. WritableSpan
+// truncated to at most `n` elements. Elements written to the returned slice can be made available
+// to the consumer using CommitN.
+// ReserveN should be called by the producer.
+func (q *Queue[T]) ReserveN(n uint64) []T {
+	span := q.WritableSpan()
+	if uint64(len(span)) > n {
+		span = span[:n]
+	}
+
+	return span
+}
+
+// PushSlice adds as many elements of `els` to the queue as fit, in order, and returns the elements
+// which did not fit, i.e. a suffix of `els`, or nil if all of them were added. The elements are
+// copied into the free slots, in two parts if the free region wraps around the end of the
+// underlying buffer, and published to the consumer at once. PushSlice does not block; if the queue
+// has been closed, no elements are added and `els` is returned.
+// PushSlice should be called by the producer.
+func (q *Queue[T]) PushSlice(els []T) []T {
+	q.checkProducer()
+	if q.closed != 0 {
+		return els
+	}
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+
+	n := q.Cap() - q.length(q.rIdxCached, q.wIdx)
+	if uint64(len(els)) < n {
+		n = uint64(len(els))
+	}
+	if n > 0 {
+		if q.reclaim != nil {
+			q.reclaimSlots(q.wIdx, n)
+		}
+		m := copy(q.items[q.slot(q.wIdx):], els[:n])
+		copy(q.items, els[m:n])
+		q.publish(q.add(q.wIdx, n))
+	}
+	if n == uint64(len(els)) {
+		return nil
+	}
+
+	return els[n:]
+}
+
+// WaitForSpace blocks until at least `n` slots at the back of the queue are free. Once it returns,
+// ReserveN(n) returns `n` slots, unless the free region wraps around the end of the underlying
+// buffer; in that case ReserveN returns the slots up to the end of the buffer, and the remaining
+// slots are returned by the next call to ReserveN after committing the first ones. WaitForSpace
+// panics if `n` exceeds the capacity of the queue, since it would never return.
+// WaitForSpace should be called by the producer.
+func (q *Queue[T]) WaitForSpace(n uint64) {
+	q.checkProducer()
+	if n > q.Cap() {
+		panic(fmt.Sprintf("spscqueue: waiting for %v free slots in queue of capacity %v", n, q.Cap()))
+	}
+
+	var spins uint64
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	for q.Cap()-q.length(q.rIdxCached, q.wIdx) < n {
+		q.waitStrategy().Wait(spins)
+		spins++
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	}
+}
+
+// WaitBelow blocks until the queue holds at most `threshold` elements. Used after filling the
+// queue, WaitBelow lets the producer hold off until the consumer has worked through a large part
+// of the backlog, rather than resuming as soon as a single slot is free, so that the producer does
+// not contend with the consumer over the boundary of a full queue. WaitBelow returns immediately if
+// `threshold` is at least the capacity of the queue.
+// WaitBelow should be called by the producer.
+func (q *Queue[T]) WaitBelow(threshold uint64) {
+	q.checkProducer()
+	var spins uint64
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	for q.length(q.rIdxCached, q.wIdx) > threshold {
+		q.waitStrategy().Wait(spins)
+		spins++
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	}
+}
+
+// CommitN advances the back of the queue by `n` elements, making them available to the consumer.
+// CommitN can be used in conjunction with WritableSpan to present the filled prefix of the span to
+// the consumer. `n` must not exceed the length of the span most recently returned by
+// WritableSpan.
+// CommitN should be called by the producer.
+func (q *Queue[T]) CommitN(n uint64) {
+	q.checkProducer()
+	q.publish(q.add(q.wIdx, n))
+}
+
+// ReadableSpan returns the contiguous region of elements at the front of the queue. The consumer
+// may process any prefix of the span and remove the first `n` elements from the queue using
+// AdvanceN(n). The span ends at the end of the underlying buffer or at the back of the queue,
+// whichever comes first, so it may be shorter than Len when the queued elements wrap around. An
+// empty span is returned if the queue is empty.
+// The span is invalidated by any call to Advance, AdvanceN or Pop.
+// ReadableSpan should be called by the consumer.
+func (q *Queue[T]) ReadableSpan() []T {
+	q.checkConsumer()
+	// Always refresh the cached write index; a stale value would needlessly shorten the span.
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+
+	start := q.slot(q.rIdx)
+	n := q.length(q.rIdx, q.wIdxCached)
+	if end := uint64(len(q.items)) - start; n > end {
+		n = end
+	}
+
+	return q.items[start : start+n]
+}
+
+// ReadableSpans returns all elements in the queue as up to two contiguous regions of the underlying
+// buffer, without copying them: the elements up to the end of the buffer, followed by the elements
+// which wrapped around to its start. The second span is empty unless the queued elements wrap
+// around. The consumer may process any prefix of the concatenated spans and remove the first `n`
+// elements from the queue using AdvanceN(n).
+// The spans are invalidated by any call to Advance, AdvanceN or Pop.
+// ReadableSpans should be called by the consumer.
+func (q *Queue[T]) ReadableSpans() ([]T, []T) {
+	q.checkConsumer()
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+
+	start := q.slot(q.rIdx)
+	n := q.length(q.rIdx, q.wIdxCached)
+	if end := uint64(len(q.items)) - start; n > end {
+		return q.items[start:], q.items[:n-end]
+	}
+
+	return q.items[start : start+n], nil
+}
+
+// Available combines ReadableSpans and Len. It returns the two contiguous regions of elements
+// returned by ReadableSpans, followed by the total number of elements in them, all based on a
+// single load of the producer's position.
+// The spans are invalidated by any call to Advance, AdvanceN or Pop.
+// Available should be called by the consumer.
+func (q *Queue[T]) Available() (first, second []T, total uint64) {
+	first, second = q.ReadableSpans()
+
+	return first, second, uint64(len(first) + len(second))
+}
+
+// AdvanceN moves the consumer forward by `n` elements. AdvanceN can be used in conjunction with
+// ReadableSpan to remove the processed prefix of the span from the queue. `n` must not exceed the
+// length of the span most recently returned by ReadableSpan; AdvanceN panics if fewer than `n`
+// elements are in the queue.
+// AdvanceN should be called by the consumer.
+func (q *Queue[T]) AdvanceN(n uint64) {
+	q.checkConsumer()
+	if n > q.length(q.rIdx, q.wIdxCached) {
+		q.checkAvailable(n, "AdvanceN")
+	}
+	q.poisonFront(n)
+	if q.stats {
+		q.recordWrap(&q.consumerWraps, q.rIdx, n)
+	}
+	if q.dwell != nil && n > 0 {
+		q.recordDwell(n)
+	}
+	q.storeIndex(&q.rIdx, &q.consumerSeq, q.add(q.rIdx, n))
+	if q.futex != nil {
+		q.futex.notFull.wake()
+	}
+}
+
+// peek copies elements into `dst`, starting `offset` elements after the front of the queue, and
+// returns the number of elements copied. Only elements known to the consumer through its cached
+// write index are copied. The consumer is not moved forward.
+// peek should be called by the consumer.
+func (q *Queue[T]) peek(dst []T, offset uint64) uint64 {
+	q.checkConsumer()
+	avail := q.length(q.rIdx, q.wIdxCached)
+	if offset >= avail {
+		return 0
+	}
+	if n := avail - offset; uint64(len(dst)) > n {
+		dst = dst[:n]
+	}
+
+	start := q.slot(q.add(q.rIdx, offset))
+	n := copy(dst, q.items[start:])
+	n += copy(dst[n:], q.items)
+
+	return uint64(n)
+}
@@ -0,0 +1,337 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Test for the WritableSpan-CommitN pattern.
+func TestWritableSpan(t *testing.T) {
+	q := New[byte](8)
+
+	span := q.WritableSpan()
+	if l := len(span); l != 8 {
+		t.Errorf("Unexpected span length; %v != 8", l)
+	}
+	copy(span, []byte("abcde"))
+	q.CommitN(5)
+	if l := q.Len(); l != 5 {
+		t.Errorf("Unexpected length; %v != 5", l)
+	}
+	for _, c := range []byte("abc") {
+		if v := q.Pop(); v != c {
+			t.Errorf("Got incorrect value; %v != %v", v, c)
+		}
+	}
+
+	// The free region now wraps; the span must stop at the end of the buffer.
+	span = q.WritableSpan()
+	if l := len(span); l != 4 {
+		t.Errorf("Unexpected span length; %v != 4", l)
+	}
+	copy(span, []byte("fghi"))
+	q.CommitN(4)
+
+	span = q.WritableSpan()
+	if l := len(span); l != 2 {
+		t.Errorf("Unexpected span length; %v != 2", l)
+	}
+	copy(span, []byte("jk"))
+	q.CommitN(2)
+
+	if span = q.WritableSpan(); len(span) != 0 {
+		t.Errorf("Got non-empty span from full queue; %v", len(span))
+	}
+	if l := q.Len(); l != 8 {
+		t.Errorf("Unexpected length; %v != 8", l)
+	}
+	for _, c := range []byte("defghijk") {
+		if v := q.Pop(); v != c {
+			t.Errorf("Got incorrect value; %v != %v", v, c)
+		}
+	}
+}
+
+// SPSC test for the WritableSpan-CommitN pattern.
+func TestWritableSpanCommitN(t *testing.T) {
+	const numItems = 10000
+	q := New[int](61)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; {
+			span := q.WritableSpan()
+			n := 0
+			for ; n < len(span) && n < 7 && i < numItems; n++ {
+				span[n] = i
+				i++
+			}
+			q.CommitN(uint64(n))
+			if n == 0 {
+				runtime.Gosched()
+			}
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			v := q.Pop()
+			if v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+}
+
+// Test for the ReadableSpan-AdvanceN pattern.
+func TestReadableSpan(t *testing.T) {
+	q := New[byte](8)
+
+	if span := q.ReadableSpan(); len(span) != 0 {
+		t.Errorf("Got non-empty span from empty queue; %v", len(span))
+	}
+
+	// Move the indices so that the payload wraps around the end of the buffer.
+	for i := 0; i < 6; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(uint64(len(q.ReadableSpan())))
+
+	const payload = "abcdefgh"
+	for _, c := range []byte(payload) {
+		q.Push(c)
+	}
+
+	var got []byte
+	for i := 0; i < 2; i++ {
+		span := q.ReadableSpan()
+		got = append(got, span...)
+		q.AdvanceN(uint64(len(span)))
+	}
+	if string(got) != payload {
+		t.Errorf("Got incorrect value; %q != %q", got, payload)
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test for reading wrapped elements through a pair of spans.
+func TestReadableSpans(t *testing.T) {
+	q := New[byte](8)
+
+	if first, second := q.ReadableSpans(); len(first) != 0 || len(second) != 0 {
+		t.Errorf("Got non-empty spans from empty queue; %v, %v", len(first), len(second))
+	}
+
+	for _, c := range []byte("abc") {
+		q.Push(c)
+	}
+	if first, second := q.ReadableSpans(); string(first) != "abc" || len(second) != 0 {
+		t.Errorf("Got incorrect spans; %q, %q", first, second)
+	}
+
+	// Move the indices so that the payload wraps around the end of the buffer.
+	q.AdvanceN(3)
+	for i := 0; i < 3; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(3)
+
+	const payload = "abcdefgh"
+	for _, c := range []byte(payload) {
+		q.Push(c)
+	}
+	first, second := q.ReadableSpans()
+	if len(second) == 0 {
+		t.Error("Got empty second span for wrapped payload")
+	}
+	if got := string(first) + string(second); got != payload {
+		t.Errorf("Got incorrect value; %q != %q", got, payload)
+	}
+	q.AdvanceN(uint64(len(first) + len(second)))
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test that Available reports the spans of wrapped elements along with their total.
+func TestAvailable(t *testing.T) {
+	q := New[byte](8)
+	if first, second, total := q.Available(); len(first) != 0 || len(second) != 0 || total != 0 {
+		t.Errorf("Got non-empty spans from empty queue; %v, %v, %v", len(first), len(second), total)
+	}
+
+	// Move the indices so that the payload wraps around the end of the buffer.
+	for i := 0; i < 6; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(6)
+
+	const payload = "abcdefg"
+	for _, c := range []byte(payload) {
+		q.Push(c)
+	}
+	first, second, total := q.Available()
+	if string(first) != "abc" || string(second) != "defg" {
+		t.Errorf("Got incorrect spans; %q, %q", first, second)
+	}
+	if total != uint64(len(first)+len(second)) || total != q.Len() {
+		t.Errorf("Unexpected total; %v != %v", total, q.Len())
+	}
+}
+
+// SPSC test for the WritableSpan-CommitN and ReadableSpan-AdvanceN patterns.
+func TestSpanRoundTrip(t *testing.T) {
+	const numItems = 10000
+	q := New[int](61)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; {
+			span := q.WritableSpan()
+			n := 0
+			for ; n < len(span) && i < numItems; n++ {
+				span[n] = i
+				i++
+			}
+			q.CommitN(uint64(n))
+			if n == 0 {
+				runtime.Gosched()
+			}
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; {
+			span := q.ReadableSpan()
+			n := 0
+			for ; n < len(span) && n < 5; n++ {
+				if span[n] != i {
+					t.Errorf("Got incorrect value; %v != %v", span[n], i)
+				}
+				i++
+			}
+			q.AdvanceN(uint64(n))
+			if n == 0 {
+				runtime.Gosched()
+			}
+		}
+	}(&wg)
+
+	wg.Wait()
+}
+
+// Test for adding what fits of a slice and returning the rest.
+func TestPushSlice(t *testing.T) {
+	q := New[int](4)
+
+	// Move the indices so that the free region wraps around the end of the buffer.
+	for i := 0; i < 3; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(3)
+
+	els := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	left := q.PushSlice(els)
+	if len(left) != 6 {
+		t.Fatalf("Unexpected leftover length; %v != 6", len(left))
+	}
+	for i, v := range left {
+		if v != 4+i {
+			t.Errorf("Got incorrect leftover value; %v != %v", v, 4+i)
+		}
+	}
+	if left = q.PushSlice(left); len(left) != 6 {
+		t.Errorf("Added elements to full queue; %v", 6-len(left))
+	}
+
+	for i := 0; i < 4; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+	if left = q.PushSlice(els[:2]); left != nil {
+		t.Errorf("Unexpected leftover; %v", left)
+	}
+	if l := q.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+}
+
+// Test for waiting on free space with a slow consumer.
+func TestWaitForSpace(t *testing.T) {
+	q := New[int](8)
+	for i := 0; i < 7; i++ {
+		q.Push(i)
+	}
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			time.Sleep(time.Millisecond)
+			q.Pop()
+		}
+	}()
+
+	q.WaitForSpace(5)
+	if l := q.Len(); l > 3 {
+		t.Errorf("Returned with too little free space; length %v > 3", l)
+	}
+
+	// The free region wraps around, so it is reserved in two parts.
+	span := q.ReserveN(5)
+	if l := len(span); l != 2 {
+		t.Errorf("Unexpected span length; %v != 2", l)
+	}
+	q.CommitN(uint64(len(span)))
+	if span = q.ReserveN(3); len(span) != 3 {
+		t.Errorf("Unexpected span length; %v != 3", len(span))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Waiting for more space than the capacity did not panic")
+		}
+	}()
+	q.WaitForSpace(9)
+}
+
+// Test for waiting until a slow consumer has drained the queue below a threshold.
+func TestWaitBelow(t *testing.T) {
+	q := New[int](8)
+	for i := 0; i < 8; i++ {
+		q.Push(i)
+	}
+	q.WaitBelow(8)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < 8; i++ {
+			time.Sleep(time.Millisecond)
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	q.WaitBelow(3)
+	if l := q.Len(); l > 3 {
+		t.Errorf("Returned above the threshold; length %v > 3", l)
+	}
+	q.WaitBelow(0)
+	wg.Wait()
+}
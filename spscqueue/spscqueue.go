@@ -1,10 +1,11 @@
 package spscqueue
 
 import (
-	"runtime"
+	"fmt"
+	"math/bits"
+	"sync"
 	"sync/atomic"
-
-	"golang.org/x/sys/cpu"
+	"time"
 )
 
 // Queue is the structure responsible for tracking the state of the bounded single-producer
@@ -12,24 +13,224 @@ import (
 type Queue[T any] struct {
 	// Relevant struct elements are spaced out to separate cache lines, so as to prevent false
 	// sharing/cache line invalidation.
-	_          cpu.CacheLinePad
-	items      []T
-	_          cpu.CacheLinePad
-	rIdx       uint64
-	wIdxCached uint64
-	_          cpu.CacheLinePad
-	wIdx       uint64
-	rIdxCached uint64
-	_          cpu.CacheLinePad
+	//
+	// Elements are published through the read and write indices: the producer writes a slot before
+	// storing the write index which covers it, and the consumer reads a slot only after loading a
+	// write index which covers it (and vice versa for freeing slots through the read index). The
+	// index stores and loads use sync/atomic, which the Go memory model defines to be sequentially
+	// consistent, so they act as release and acquire barriers on every platform, including weakly
+	// ordered ones such as arm64. Any change replacing them with plain or relaxed accesses must add
+	// explicit barriers instead; TestPublicationOrdering checks this guarantee.
+	_             cacheLinePad
+	items         []T
+	monotonic     bool
+	mask          uint64
+	period        uint64
+	stats         bool
+	firstTouch    Role
+	seqLen        bool
+	producerFast  bool
+	pushFast      bool
+	consumerFast  bool
+	overflow      *Queue[T]
+	futex         *futexPair
+	dwell         []int64
+	dwellBase     time.Time
+	deadlock      time.Duration
+	wait          atomic.Value
+	_             cacheLinePad
+	rIdx          uint64
+	wIdxCached    uint64
+	consumerSpins *spinHistogram
+	consumerWraps uint64
+	consumerWaits uint64
+	consumerSeq   uint64
+	consumerPeer  uint64
+	consumerMoved bool
+	dwellLast     int64
+	dwellMax      int64
+	_             cacheLinePad
+	wIdx          uint64
+	rIdxCached    uint64
+	producerSpins *spinHistogram
+	producerWraps uint64
+	producerWaits uint64
+	producerSeq   uint64
+	producerPeer  uint64
+	producerMoved bool
+	producerCount uint64
+	blockCallback func(time.Duration)
+	event         *eventNotifier
+	lazyFill      func() T
+	reclaim       func(T)
+	occupied      []bool
+	filled        []bool
+	resSeq        uint64
+	pubSeq        uint64
+	completed     []bool
+	maxStall      int64
+	highWater     uint64
+	dropped       uint64
+	closed        uint32
+	_             cacheLinePad
+	debug         debugState
+	autoSize      *autoSizeConfig
+	opts          []Option
+	successor     atomic.Value
+	chanOnce      sync.Once
+	ch            chan T
+	watchOnce     sync.Once
+	watchStop     chan struct{}
 }
 
 // New[T any] returns an empty single-producer single-consumer bounded queue. The queue has capacity
-// for `size` elements of type `T`.
-func New[T any](size uint) *Queue[T] {
-	return &Queue[T]{items: make([]T, size+1)}
+// for `size` elements of type `T`. The behaviour of the queue may be adjusted by passing Options.
+func New[T any](size uint, opts ...Option) *Queue[T] {
+	q := &Queue[T]{}
+	q.init(make([]T, size+1), opts)
+
+	return q
+}
+
+// NewWithUsableCapacity[T any] returns an empty queue which can hold exactly `usable` elements of
+// type `T`, i.e. whose Cap is `usable`. It is equivalent to New, and exists to make the capacity
+// contract explicit: the underlying buffer holds `usable`+1 slots, since one slot is always kept
+// free to tell a full queue apart from an empty one, but that slot never counts towards the
+// capacity.
+func NewWithUsableCapacity[T any](usable uint, opts ...Option) *Queue[T] {
+	return New[T](usable, opts...)
+}
+
+// NewPow2[T any] returns an empty queue with capacity for at least `minSize` elements of type `T`,
+// along with its actual capacity. The underlying buffer is rounded up to the next power of two
+// larger than `minSize`, and the queue uses monotonic indices, such that it takes the power-of-two
+// indexing path reported by IsPow2. Since one slot of the buffer is always kept free, the capacity
+// is one less than the size of the buffer, e.g. 127 for a `minSize` of 100; it equals Cap. NewPow2
+// panics if the buffer would exceed the range of uint.
+func NewPow2[T any](minSize uint, opts ...Option) (*Queue[T], uint) {
+	shift := bits.Len(minSize)
+	if shift == bits.UintSize {
+		panic(fmt.Sprintf("spscqueue: capacity %v cannot be rounded up to a power of two", minSize))
+	}
+	size := uint(1)<<shift - 1
+
+	return New[T](size, append([]Option{WithMonotonicIndices()}, opts...)...), size
+}
+
+// NewSeeded[T any] returns a queue for `capacity` elements of type `T` which initially holds the
+// elements of `initial`, in order, e.g. to resume work restored from a persisted snapshot. The
+// elements are copied to the start of the underlying buffer. NewSeeded returns an error if
+// `initial` holds more than `capacity` elements. The behaviour of the queue may be adjusted by
+// passing Options.
+func NewSeeded[T any](capacity uint, initial []T, opts ...Option) (*Queue[T], error) {
+	if uint64(len(initial)) > uint64(capacity) {
+		return nil, fmt.Errorf("spscqueue: seeding queue of capacity %v with %v elements", capacity,
+			len(initial))
+	}
+
+	q := New[T](capacity, opts...)
+	n := uint64(copy(q.items, initial))
+	for i := uint64(0); i < n; i++ {
+		// The seeded elements are treated like elements added by the producer.
+		if q.filled != nil {
+			q.filled[i] = true
+		}
+		if q.occupied != nil {
+			q.occupied[i] = true
+		}
+	}
+	if q.dwell != nil {
+		q.stampDwell(0, n)
+	}
+	atomic.StoreUint64(&q.wIdx, n)
+	q.wIdxCached = n
+	q.producerCount = n
+	if q.stats {
+		q.recordHighWater()
+	}
+
+	return q, nil
+}
+
+// init prepares an empty queue which uses `items` as its underlying buffer, applying `opts`.
+func (q *Queue[T]) init(items []T, opts []Option) {
+	o := options{waitStrategy: defaultWaitStrategy}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	q.items = items
+	q.opts = opts
+	if o.spinInstrumentation {
+		q.producerSpins = &spinHistogram{}
+		q.consumerSpins = &spinHistogram{}
+	}
+	q.stats = o.stats
+	q.seqLen = o.seqLen
+	if o.diagnostics {
+		q.enableDiagnostics()
+	}
+	q.firstTouch = o.firstTouch
+	q.SetWaitStrategy(o.waitStrategy)
+	q.blockCallback = o.blockCallback
+	if o.lazyFill != nil {
+		gen, ok := o.lazyFill.(func() T)
+		if !ok {
+			panic(fmt.Sprintf("spscqueue: lazy fill generator of type %T for queue of %T",
+				o.lazyFill, *new(T)))
+		}
+		q.lazyFill = gen
+		q.filled = make([]bool, len(q.items))
+	}
+	if o.reclaim != nil {
+		reclaim, ok := o.reclaim.(func(T))
+		if !ok {
+			panic(fmt.Sprintf("spscqueue: reclaim function of type %T for queue of %T",
+				o.reclaim, *new(T)))
+		}
+		q.reclaim = reclaim
+		q.occupied = make([]bool, len(q.items))
+	}
+	q.monotonic = o.monotonic
+	q.updateIndexing()
+	if o.eventfd {
+		q.event = newEventNotifier()
+	}
+	if o.futex {
+		q.futex = newFutexPair()
+	}
+	q.deadlock = o.deadlockTimeout
+	if o.dwell {
+		q.dwell = make([]int64, len(q.items))
+		q.dwellBase = time.Now()
+	}
+	q.fastPaths()
+	if o.maxStall > 0 {
+		q.watchStop = make(chan struct{})
+		go q.watch(o.maxStall, o.onStall)
+	}
+}
+
+// fastPaths determines whether the fast paths of Offer, Push and Advance apply to the queue. The
+// fast paths cover the default configuration, i.e. wrapping indices and no options which hook into
+// publishing or freeing slots, and skip the checks for those options; everything else is handled
+// by a separate slow path. The fast path of Push additionally requires that neither an overflow
+// queue nor the spin histograms are configured. Front and the fast paths offerFast, pushFastPath
+// and advanceFast contain only inlinable calls, such as those to next and the debug checks, so they
+// stay within the inlining budget and the compiler inlines them into their callers, which
+// `go build -gcflags=-m` reports; changes to them must keep it that way. Offer, Push and Advance
+// themselves cannot be inlined: the call to the slow path alone takes up most of the inlining
+// budget, so they consist of the inlined fast path and that call. Pop uses the fast path of Advance
+// directly. BenchmarkOfferFront and BenchmarkPushPop measure the combination.
+func (q *Queue[T]) fastPaths() {
+	q.producerFast = !q.monotonic && !q.stats && !q.seqLen && q.reclaim == nil && q.event == nil &&
+		q.futex == nil && q.dwell == nil
+	q.pushFast = q.producerFast && q.overflow == nil && q.producerSpins == nil
+	q.consumerFast = !q.monotonic && !q.stats && !q.seqLen && q.futex == nil && q.dwell == nil
 }
 
 func (q *Queue[T]) Fill(f func() T) {
+	q.markPreallocated()
 	for i := 0; i < len(q.items); i++ {
 		q.items[i] = f()
 	}
@@ -38,41 +239,141 @@ func (q *Queue[T]) Fill(f func() T) {
 // Push adds the passed element to the queue. Push will block if the queue is full.
 // Push should be called by the producer.
 func (q *Queue[T]) Push(el T) {
-	wIdxNext := q.wIdx + 1
-	if wIdxNext == uint64(len(q.items)) {
-		wIdxNext = 0
+	q.checkProducer()
+	if !q.pushFastPath(el) {
+		q.pushSlow(el)
 	}
+}
 
-	// Wait if we ran into the consumer.
-	if wIdxNext == q.rIdxCached {
-		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
-		for wIdxNext == q.rIdxCached {
-			runtime.Gosched()
-			q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+// pushFastPath adds the passed element to the queue and returns true if the fast path of Push
+// applies to the queue and the producer's cached copy of the consumer's position shows a slot to be
+// available; see fastPaths. It returns false without side effects otherwise.
+// pushFastPath should be called by the producer.
+func (q *Queue[T]) pushFastPath(el T) bool {
+	if q.pushFast {
+		if wIdxNext := q.next(q.wIdx); wIdxNext != q.rIdxCached {
+			q.items[q.wIdx] = el
+			atomic.StoreUint64(&q.wIdx, wIdxNext)
+			q.producerCount++
+			return true
 		}
 	}
-	q.items[q.wIdx] = el
-	atomic.StoreUint64(&q.wIdx, wIdxNext)
+
+	return false
 }
 
-// Offer adds the passed element to the queue if there is an available slot. Offer returns true if
-// the item was added successfully, otherwise false.
+// pushSlow implements Push for the cases not covered by its fast path.
+// pushSlow should be called by the producer.
+func (q *Queue[T]) pushSlow(el T) {
+	if q.overflow != nil {
+		q.pushOverflow(el)
+		return
+	}
+	wIdxNext := q.next(q.wIdx)
+
+	// Wait if we ran into the consumer.
+	var spins uint64
+	if q.collides(wIdxNext, q.rIdxCached) {
+		spins = q.awaitConsumer(wIdxNext)
+	}
+	if q.producerSpins != nil {
+		q.producerSpins.record(spins)
+	}
+	if q.reclaim != nil {
+		q.reclaimSlots(q.wIdx, 1)
+	}
+	q.items[q.slot(q.wIdx)] = el
+	q.publish(wIdxNext)
+}
+
+// publish moves the back of the queue to `wIdx`, making the elements written to the slots before it
+// available to the consumer.
+// publish should be called by the producer.
+func (q *Queue[T]) publish(wIdx uint64) {
+	wIdxPrev := q.wIdx
+	if q.dwell != nil {
+		q.stampDwell(wIdxPrev, wIdx)
+	}
+	// The atomic store publishes the elements written by the producer to the consumer.
+	q.storeIndex(&q.wIdx, &q.producerSeq, wIdx)
+	q.producerCount += q.length(wIdxPrev, wIdx)
+	if q.stats {
+		q.recordHighWater()
+		q.recordWrap(&q.producerWraps, wIdxPrev, q.length(wIdxPrev, wIdx))
+	}
+	if q.reclaim != nil {
+		q.markOccupied(wIdxPrev, wIdx)
+	}
+	if q.event != nil {
+		q.notify(wIdxPrev)
+	}
+	if q.futex != nil {
+		q.futex.notEmpty.wake()
+	}
+}
+
+// Offer adds the passed element to the queue if there is an available slot and the queue has not
+// been closed. Offer returns true if the item was added successfully, otherwise false. OfferE
+// reports the reason for a failure. Failures are counted by DroppedCount.
 // Offer should be called by the producer.
 func (q *Queue[T]) Offer(el T) bool {
-	wIdxNext := q.wIdx + 1
-	if wIdxNext == uint64(len(q.items)) {
-		wIdxNext = 0
+	q.checkProducer()
+	if q.offerFast(el) {
+		return true
+	}
+
+	return q.offerSlow(el)
+}
+
+// offerFast adds the passed element to the queue and returns true if the queue is in the default
+// configuration, is not closed and has a slot available as far as the producer's cached copy of
+// the consumer's position shows; see fastPaths. It returns false without side effects otherwise.
+// offerFast should be called by the producer.
+func (q *Queue[T]) offerFast(el T) bool {
+	if q.producerFast && q.closed == 0 {
+		if wIdxNext := q.next(q.wIdx); wIdxNext != q.rIdxCached {
+			q.items[q.wIdx] = el
+			atomic.StoreUint64(&q.wIdx, wIdxNext)
+			q.producerCount++
+			return true
+		}
+	}
+
+	return false
+}
+
+// offerSlow implements Offer for the cases not covered by its fast path.
+// offerSlow should be called by the producer.
+func (q *Queue[T]) offerSlow(el T) bool {
+	if !q.tryOffer(el) {
+		q.recordDrop()
+		return false
+	}
+
+	return true
+}
+
+// tryOffer implements Offer without counting a failure to add the element as a drop, so that
+// callers which retry can count a single drop once they give up.
+// tryOffer should be called by the producer.
+func (q *Queue[T]) tryOffer(el T) bool {
+	if q.closed != 0 {
+		return false
 	}
+	wIdxNext := q.next(q.wIdx)
 
 	// Check if we ran into the consumer.
-	if wIdxNext == q.rIdxCached {
+	if q.collides(wIdxNext, q.rIdxCached) {
 		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
-		if wIdxNext == q.rIdxCached {
+		if q.collides(wIdxNext, q.rIdxCached) {
 			return false
 		}
 	}
-	q.items[q.wIdx] = el
-	atomic.StoreUint64(&q.wIdx, wIdxNext)
+	if q.reclaim != nil {
+		q.reclaimSlots(q.wIdx, 1)
+	}
+	q.items[q.slot(q.wIdx)] = el
+	q.publish(wIdxNext)
 	return true
 }
 
@@ -82,50 +383,94 @@ func (q *Queue[T]) Offer(el T) bool {
 // queue items. Subsequent calls to Reserve without a call to Commit will return the same element.
 // Reserve should be called by the producer.
 func (q *Queue[T]) Reserve() (T, bool) {
-	wIdxNext := q.wIdx + 1
-	if wIdxNext == uint64(len(q.items)) {
-		wIdxNext = 0
-	}
+	q.checkProducer()
+	wIdxNext := q.next(q.wIdx)
 
 	// Check if we ran into the consumer.
-	if wIdxNext == q.rIdxCached {
+	if q.collides(wIdxNext, q.rIdxCached) {
 		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
-		if wIdxNext == q.rIdxCached {
+		if q.collides(wIdxNext, q.rIdxCached) {
 			var ret T
 			return ret, false
 		}
 	}
 
-	return q.items[q.wIdx], true
+	return q.items[q.reservedSlot()], true
+}
+
+// ReserveWait is a blocking variant of Reserve. It waits until the next open slot at the back of
+// the queue is free and returns a pointer to it, through which the producer may work on the
+// slot's pre-allocated contents or replace them. The slot is made available to the consumer using
+// Commit. Subsequent calls to ReserveWait without a call to Commit will return the same slot.
+// ReserveWait should be called by the producer.
+func (q *Queue[T]) ReserveWait() *T {
+	q.checkProducer()
+	wIdxNext := q.next(q.wIdx)
+
+	// Wait if we ran into the consumer.
+	var spins uint64
+	if q.collides(wIdxNext, q.rIdxCached) {
+		spins = q.awaitConsumer(wIdxNext)
+	}
+	if q.producerSpins != nil {
+		q.producerSpins.record(spins)
+	}
+
+	return &q.items[q.reservedSlot()]
+}
+
+// reservedSlot returns the position in `items` of the slot handed out by Reserve and ReserveWait,
+// reclaiming its previous occupant if WithReclaim is in effect, and initialising it first if
+// WithLazyFill is in effect.
+func (q *Queue[T]) reservedSlot() uint64 {
+	if q.reclaim != nil {
+		q.reclaimSlots(q.wIdx, 1)
+	}
+	slot := q.slot(q.wIdx)
+	if q.lazyFill != nil && !q.filled[slot] {
+		q.items[slot] = q.lazyFill()
+		q.filled[slot] = true
+	}
+
+	return slot
 }
 
 // Commit advances the back of the queue. Commit can be used in conjunction with Reserve to work on
 // the underlying queue data and present them to the consumer.
 // Commit should be called by the producer.
 func (q *Queue[T]) Commit() {
-	wIdxNext := q.wIdx + 1
-	if wIdxNext == uint64(len(q.items)) {
-		wIdxNext = 0
-	}
-	atomic.StoreUint64(&q.wIdx, wIdxNext)
+	q.checkProducer()
+	wIdxNext := q.next(q.wIdx)
+	q.publish(wIdxNext)
 }
 
 // Pop returns the oldest element in the queue and removes it. Pop will block if no element is
 // available.
 // Pop should be called by the consumer.
 func (q *Queue[T]) Pop() T {
-	defer q.Advance()
+	q.checkConsumer()
+	if q.overflow != nil {
+		return q.popOverflow()
+	}
 	// Wait for an item to be available.
 	rIdx := q.rIdx
+	var spins uint64
 	if rIdx == q.wIdxCached {
-		q.wIdxCached = atomic.LoadUint64(&q.wIdx)
-		for rIdx == q.wIdxCached {
-			runtime.Gosched()
-			q.wIdxCached = atomic.LoadUint64(&q.wIdx)
-		}
+		spins = q.awaitProducer()
+	}
+	if q.consumerSpins != nil {
+		q.consumerSpins.record(spins)
+	}
+
+	// The element is copied out before advancing, since the producer may reuse the slot as soon as
+	// the consumer has moved past it. Advancing is not deferred, so that a panic raised while
+	// waiting does not also advance past an element which was never there.
+	el := q.items[q.slot(rIdx)]
+	if !q.advanceFast() {
+		q.advanceSlow()
 	}
 
-	return q.items[rIdx]
+	return el
 }
 
 // Front is a non-blocking variant of Pop. It returns the oldest element in the queue if the queue
@@ -134,6 +479,8 @@ func (q *Queue[T]) Pop() T {
 // Advance will return the same element.
 // Front should be called by the consumer.
 func (q *Queue[T]) Front() (T, bool) {
+	// Front is small enough to be inlined; see fastPaths.
+	q.checkConsumer()
 	// Check if an item is available.
 	if q.rIdx == q.wIdxCached {
 		q.wIdxCached = atomic.LoadUint64(&q.wIdx)
@@ -143,30 +490,167 @@ func (q *Queue[T]) Front() (T, bool) {
 		}
 	}
 
-	return q.items[q.rIdx], true
+	return q.items[q.slot(q.rIdx)], true
+}
+
+// FrontBlocking is a blocking variant of Front. It waits until an element is available and returns
+// the oldest element in the queue without removing it. As with Front, subsequent calls to
+// FrontBlocking without a call to Advance will return the same element.
+// FrontBlocking should be called by the consumer.
+func (q *Queue[T]) FrontBlocking() T {
+	q.checkConsumer()
+	if q.rIdx == q.wIdxCached {
+		q.awaitProducer()
+	}
+
+	return q.items[q.slot(q.rIdx)]
+}
+
+// PopPtr is a variant of Front which returns a pointer to the oldest element in the queue rather
+// than a copy of it, which avoids copying large elements. A boolean indicator of success or
+// failure is included as a second return value; PopPtr returns nil and false if the queue is
+// empty. The element remains in the queue until the consumer calls Advance. The pointer refers to
+// the underlying buffer, and is only valid until then: once the consumer has moved past the slot,
+// the producer may overwrite it at any time.
+// PopPtr should be called by the consumer.
+func (q *Queue[T]) PopPtr() (*T, bool) {
+	q.checkConsumer()
+	if q.rIdx == q.wIdxCached {
+		q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+		if q.rIdx == q.wIdxCached {
+			return nil, false
+		}
+	}
+
+	return &q.items[q.slot(q.rIdx)], true
+}
+
+// FrontAndLen combines Front and Len. It returns the oldest element in the queue without removing
+// it, followed by the number of elements in the queue, which are based on a single load of the
+// producer's position and thus consistent with each other. If the queue is empty, FrontAndLen
+// returns the zero-value for the type, 0 and false.
+// FrontAndLen should be called by the consumer.
+func (q *Queue[T]) FrontAndLen() (T, uint64, bool) {
+	q.checkConsumer()
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	n := q.length(q.rIdx, q.wIdxCached)
+	if n == 0 {
+		var t T
+		return t, 0, false
+	}
+
+	return q.items[q.slot(q.rIdx)], n, true
+}
+
+// FrontAndPressure combines Front and Pressure. It returns the oldest element in the queue without
+// removing it, followed by the fill fraction of the queue, which are based on a single load of the
+// producer's position and thus consistent with each other. If the queue is empty, FrontAndPressure
+// returns the zero-value for the type, 0 and false.
+// FrontAndPressure should be called by the consumer.
+func (q *Queue[T]) FrontAndPressure() (T, float64, bool) {
+	el, n, ok := q.FrontAndLen()
+	if !ok {
+		return el, 0, false
+	}
+
+	return el, q.pressure(n), true
+}
+
+// SwapFront replaces the oldest element in the queue with `newVal` without removing it, and returns
+// the element which was replaced. If the queue is empty, SwapFront returns the zero-value for the
+// type and false, and the queue is left unchanged. A subsequent call to Front or Pop will return
+// `newVal`.
+// SwapFront should be called by the consumer.
+func (q *Queue[T]) SwapFront(newVal T) (T, bool) {
+	old, ok := q.Front()
+	if ok {
+		// The producer never writes to occupied slots, so the consumer may safely modify it.
+		q.items[q.slot(q.rIdx)] = newVal
+	}
+
+	return old, ok
+}
+
+// PeekFunc passes the oldest element in the queue to `f` without removing it, and removes it if `f`
+// returns true. PeekFunc returns whether the element was removed; if the queue is empty, `f` is not
+// called and PeekFunc returns false.
+// PeekFunc should be called by the consumer.
+func (q *Queue[T]) PeekFunc(f func(T) bool) bool {
+	el, ok := q.Front()
+	if !ok || !f(el) {
+		return false
+	}
+	q.Advance()
+
+	return true
 }
 
 // Advance moves the consumer forward. Advance may be called after using the data returned from
-// Front.
+// Front. Advance panics if the queue is empty.
 // Advance should be called by the consumer if and only if it follows a successful call to Front.
 func (q *Queue[T]) Advance() {
-	rIdxNext := q.rIdx + 1
-	if rIdxNext == uint64(len(q.items)) {
-		rIdxNext = 0
+	q.checkConsumer()
+	if !q.advanceFast() {
+		q.advanceSlow()
+	}
+}
+
+// advanceFast moves the consumer forward and returns true if the queue is in the default
+// configuration and the consumer's cached copy of the producer's position shows that the queue is
+// not empty; see fastPaths. It returns false without side effects otherwise.
+// advanceFast should be called by the consumer.
+func (q *Queue[T]) advanceFast() bool {
+	if q.consumerFast && q.rIdx != q.wIdxCached {
+		q.poisonFront(1)
+		atomic.StoreUint64(&q.rIdx, q.next(q.rIdx))
+		return true
+	}
+
+	return false
+}
+
+// advanceSlow implements Advance for the cases not covered by its fast path.
+// advanceSlow should be called by the consumer.
+func (q *Queue[T]) advanceSlow() {
+	if q.rIdx == q.wIdxCached {
+		q.checkAvailable(1, "Advance")
+	}
+	q.poisonFront(1)
+	if q.stats {
+		q.recordWrap(&q.consumerWraps, q.rIdx, 1)
+	}
+	if q.dwell != nil {
+		q.recordDwell(1)
+	}
+	q.storeIndex(&q.rIdx, &q.consumerSeq, q.next(q.rIdx))
+	if q.futex != nil {
+		q.futex.notFull.wake()
 	}
-	atomic.StoreUint64(&q.rIdx, rIdxNext)
 }
 
-// Len returns the number of elements in the queue.
+// Len returns the number of elements in the queue. When called by a thread other than the
+// producer and the consumer, the result is only a snapshot and may be outdated by the time it is
+// used; it is however guaranteed not to exceed the capacity.
 // Any thread may call Len.
 func (q *Queue[T]) Len() uint64 {
 	rIdx := atomic.LoadUint64(&q.rIdx)
-	wIdx := atomic.LoadUint64(&q.wIdx)
-	if wIdx == rIdx {
-		return 0
-	} else if wIdx > rIdx {
-		return wIdx - rIdx
-	}
+	return q.observedLength(rIdx, atomic.LoadUint64(&q.wIdx))
+}
+
+// MaybeEmpty is a cheap approximation of checking whether the queue is empty, which performs no
+// atomic operations. It compares the consumer's position against its cached copy of the producer's
+// position, as of the consumer's last refresh of that copy. Consumer methods refresh it whenever
+// the copy indicates that the queue is empty, and ReadableSpan and FrontAndLen refresh it on every
+// call. A false result is exact: the queue holds at least one element, and Front will succeed. A
+// true result may be stale, since the producer may have added elements after the last refresh; the
+// consumer must then fall back to a real check such as Front to find out.
+// MaybeEmpty should be called by the consumer.
+func (q *Queue[T]) MaybeEmpty() bool {
+	return q.rIdx == q.wIdxCached
+}
 
-	return uint64(len(q.items)) - (rIdx - wIdx)
+// Cap returns the maximum number of elements the queue can hold.
+// Any thread may call Cap.
+func (q *Queue[T]) Cap() uint64 {
+	return uint64(len(q.items)) - 1
 }
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestLength(t *testing.T) {
@@ -54,6 +55,51 @@ func TestLength(t *testing.T) {
 	}
 }
 
+// Test that a queue holds exactly its usable capacity.
+func TestUsableCapacity(t *testing.T) {
+	for _, usable := range []uint{0, 1, 7, 8} {
+		q := NewWithUsableCapacity[int](usable)
+		if c := q.Cap(); c != uint64(usable) {
+			t.Errorf("Unexpected capacity; %v != %v", c, usable)
+		}
+		for i := 0; i < int(usable); i++ {
+			if !q.Offer(i) {
+				t.Errorf("Failed to add element %v to queue of capacity %v", i, usable)
+			}
+		}
+		if q.Offer(-1) {
+			t.Errorf("Managed to add element to full queue of capacity %v", usable)
+		}
+		if l := q.Len(); l != uint64(usable) {
+			t.Errorf("Unexpected length; %v != %v", l, usable)
+		}
+	}
+}
+
+// Test that a seeded queue yields its initial contents before any pushed elements.
+func TestNewSeeded(t *testing.T) {
+	if _, err := NewSeeded[int](2, []int{1, 2, 3}); err == nil {
+		t.Error("Seeded queue with more elements than its capacity")
+	}
+
+	q, err := NewSeeded[int](4, []int{1, 2, 3}, WithStats())
+	if err != nil {
+		t.Fatalf("Failed to seed queue; %v", err)
+	}
+	if l, c, high := q.Stats(); l != 3 || c != 4 || high != 3 {
+		t.Errorf("Unexpected stats; %v, %v, %v != 3, 4, 3", l, c, high)
+	}
+	q.Push(4)
+	if q.Offer(5) {
+		t.Error("Managed to add element to full seeded queue")
+	}
+	for i := 1; i <= 4; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+}
+
 // Simple single threaded test.
 func TestPushPopSimple(t *testing.T) {
 	q := New[int](8)
@@ -163,6 +209,184 @@ func TestOfferPeekAdvance(t *testing.T) {
 	wg.Wait()
 }
 
+// SPSC test for waiting on the front of the queue with a slow producer.
+func TestFrontBlocking(t *testing.T) {
+	const numItems = 100
+	q := New[int](4)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			if i%10 == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			q.Push(i)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			v := q.FrontBlocking()
+			if v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+			// The element stays at the front until the consumer advances.
+			if v2 := q.FrontBlocking(); v2 != v {
+				t.Errorf("Got incorrect value; %v != %v", v2, v)
+			}
+			q.Advance()
+		}
+	}(&wg)
+
+	wg.Wait()
+}
+
+// Test that FrontAndLen agrees with separate calls to Front and Len.
+func TestFrontAndLen(t *testing.T) {
+	q := New[int](4)
+	if v, l, ok := q.FrontAndLen(); ok || l != 0 || v != 0 {
+		t.Errorf("Got element of empty queue; %v, %v", v, l)
+	}
+
+	check := func() {
+		v, l, ok := q.FrontAndLen()
+		front, _ := q.Front()
+		if !ok || v != front {
+			t.Errorf("Got incorrect value; %v != %v", v, front)
+		}
+		if l != q.Len() {
+			t.Errorf("Unexpected length; %v != %v", l, q.Len())
+		}
+	}
+	for i := 1; i <= 4; i++ {
+		q.Push(i)
+		check()
+	}
+	for i := 1; i < 4; i++ {
+		q.Pop()
+		check()
+	}
+}
+
+// Test that MaybeEmpty relies on the consumer's cached view of the producer.
+func TestMaybeEmpty(t *testing.T) {
+	q := New[int](4)
+	if !q.MaybeEmpty() {
+		t.Error("New queue not reported as maybe empty")
+	}
+
+	// The consumer has not refreshed its view yet, so MaybeEmpty lags behind Len.
+	q.Push(1)
+	q.Push(2)
+	if !q.MaybeEmpty() || q.Len() != 2 {
+		t.Errorf("MaybeEmpty did not lag behind Len; %v", q.Len())
+	}
+
+	// Front refreshes the view.
+	q.Front()
+	if q.MaybeEmpty() {
+		t.Error("Non-empty queue reported as maybe empty after refresh")
+	}
+	q.Pop()
+	if q.MaybeEmpty() {
+		t.Error("Non-empty queue reported as maybe empty")
+	}
+	q.Pop()
+	if !q.MaybeEmpty() {
+		t.Error("Empty queue not reported as maybe empty")
+	}
+}
+
+// Test for reading large elements in place through PopPtr.
+func TestPopPtr(t *testing.T) {
+	type large struct {
+		id      int
+		payload [512]byte
+	}
+	q := New[large](2)
+	if p, ok := q.PopPtr(); ok || p != nil {
+		t.Errorf("Got pointer into empty queue; %p", p)
+	}
+
+	for i := 0; i < 5; i++ {
+		el := large{id: i}
+		el.payload[len(el.payload)-1] = byte(i)
+		q.Push(el)
+
+		p, ok := q.PopPtr()
+		if !ok {
+			t.Fatal("Failed to get pointer into non-empty queue")
+		}
+		if p.id != i || p.payload[len(p.payload)-1] != byte(i) {
+			t.Errorf("Got incorrect value; %v != %v", p.id, i)
+		}
+		// The element remains in the queue until the consumer advances.
+		if l := q.Len(); l != 1 {
+			t.Errorf("Unexpected length; %v != 1", l)
+		}
+		if p2, _ := q.PopPtr(); p2 != p {
+			t.Errorf("Got pointer to a different slot; %p != %p", p2, p)
+		}
+		q.Advance()
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test for replacing the front element.
+func TestSwapFront(t *testing.T) {
+	q := New[int](4)
+	if v, ok := q.SwapFront(1); ok || v != 0 {
+		t.Errorf("Managed to swap element of empty queue; %v", v)
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+
+	q.Push(1)
+	q.Push(2)
+	if v, ok := q.SwapFront(10); !ok || v != 1 {
+		t.Errorf("Got incorrect value; %v != 1", v)
+	}
+	if v, ok := q.SwapFront(11); !ok || v != 10 {
+		t.Errorf("Got incorrect value; %v != 10", v)
+	}
+	if l := q.Len(); l != 2 {
+		t.Errorf("Unexpected length; %v != 2", l)
+	}
+	if v := q.Pop(); v != 11 {
+		t.Errorf("Got incorrect value; %v != 11", v)
+	}
+	if v := q.Pop(); v != 2 {
+		t.Errorf("Got incorrect value; %v != 2", v)
+	}
+}
+
+// Test for conditionally removing the front element.
+func TestPeekFunc(t *testing.T) {
+	q := New[int](4)
+	if q.PeekFunc(func(int) bool { t.Error("Called with element of empty queue"); return true }) {
+		t.Error("Removed element from empty queue")
+	}
+
+	q.Push(1)
+	q.Push(2)
+	if q.PeekFunc(func(v int) bool { return v == 2 }) {
+		t.Error("Removed element which was rejected")
+	}
+	if !q.PeekFunc(func(v int) bool { return v == 1 }) {
+		t.Error("Failed to remove accepted element")
+	}
+	if v := q.Pop(); v != 2 {
+		t.Errorf("Got incorrect value; %v != 2", v)
+	}
+}
+
 // Test for the Reserve-Commit pattern.
 func TestReserveCommit(t *testing.T) {
 	const numItems = 10000
@@ -201,6 +425,70 @@ func TestReserveCommit(t *testing.T) {
 	wg.Wait()
 }
 
+// Test that ReserveWait blocks until a slow consumer frees a slot.
+func TestReserveWait(t *testing.T) {
+	const numItems = 20
+	q := New[int](2)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			time.Sleep(time.Millisecond)
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	for i := 0; i < numItems; i++ {
+		p := q.ReserveWait()
+		if q.ReserveWait() != p {
+			t.Error("Repeated ReserveWait returned a different slot")
+		}
+		*p = i
+		q.Commit()
+	}
+	wg.Wait()
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test for lazily filling the underlying queue storage through Reserve.
+func TestLazyFill(t *testing.T) {
+	var generated int
+	q := New[*int](4, WithLazyFill(func() *int { generated++; return new(int) }))
+	if generated != 0 {
+		t.Errorf("Unexpected number of generated elements; %v != 0", generated)
+	}
+
+	seen := map[*int]int{}
+	for i := 0; i < 20; i++ {
+		v, ok := q.Reserve()
+		if !ok {
+			t.Fatal("Failed to reserve element in non-full queue")
+		}
+		*v = i
+		seen[v]++
+		q.Commit()
+		if v := q.Pop(); *v != i {
+			t.Errorf("Got incorrect value; %v != %v", *v, i)
+		}
+	}
+
+	// Each of the 5 underlying slots must have been generated exactly once and then reused.
+	if generated != 5 {
+		t.Errorf("Unexpected number of generated elements; %v != 5", generated)
+	}
+	for v, n := range seen {
+		if n != 4 {
+			t.Errorf("Slot %p reserved %v times; expected 4", v, n)
+		}
+	}
+}
+
 // Test for a string type.
 func TestString(t *testing.T) {
 	const numItems = 10000
@@ -229,6 +517,48 @@ func TestString(t *testing.T) {
 	wg.Wait()
 }
 
+// Test that element contents are visible to the consumer once an element is published. Each element
+// spans several words which are written non-atomically, so a missing publication barrier shows up
+// as a torn element on weakly ordered platforms, and as a data race under the race detector.
+func TestPublicationOrdering(t *testing.T) {
+	const numItems = 100000
+	type element [8]uint64
+	q := New[element](7)
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := uint64(0); i < numItems; i++ {
+			var el element
+			for j := range el {
+				el[j] = i
+			}
+			q.Push(el)
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := uint64(0); i < numItems; i++ {
+			el, ok := q.Front()
+			for !ok {
+				runtime.Gosched()
+				el, ok = q.Front()
+			}
+			for j := range el {
+				if el[j] != i {
+					t.Errorf("Got torn element %v; word %v is %v", i, j, el[j])
+				}
+			}
+			q.Advance()
+		}
+	}(&wg)
+
+	wg.Wait()
+}
+
 // Single threaded benchmark; not the primary usecase.
 func BenchmarkPushPopSingleThread(b *testing.B) {
 	q := New[int](1)
@@ -240,6 +570,19 @@ func BenchmarkPushPopSingleThread(b *testing.B) {
 	}
 }
 
+// Single threaded benchmark of the non-blocking operations on their fast paths.
+func BenchmarkOfferFront(b *testing.B) {
+	q := New[int](64)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Offer(i)
+		if _, ok := q.Front(); ok {
+			q.Advance()
+		}
+	}
+}
+
 // SPSC benchmark.
 func BenchmarkPushPop(b *testing.B) {
 	q := New[int](1024)
@@ -0,0 +1,134 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// recordHighWater updates the high-water mark from the current length of the queue. It is called
+// by the producer after publishing new elements.
+func (q *Queue[T]) recordHighWater() {
+	if l := q.observedLength(atomic.LoadUint64(&q.rIdx), q.wIdx); l > q.highWater {
+		atomic.StoreUint64(&q.highWater, l)
+	}
+}
+
+// recordWrap increments the wrap counter `count` if moving `n` positions on from `idx` crosses the
+// end of the underlying buffer, i.e. returns to its first slot. It is called by the role owning the
+// counter before moving its index.
+func (q *Queue[T]) recordWrap(count *uint64, idx, n uint64) {
+	if q.slot(idx)+n >= uint64(len(q.items)) {
+		atomic.StoreUint64(count, *count+1)
+	}
+}
+
+// WrapCounts returns the number of times the producer and the consumer have wrapped around the end
+// of the underlying buffer, i.e. have moved from its last slot back to its first one. Comparing the
+// counts against the number of elements processed indicates how much of the buffer the working set
+// spans. WrapCounts returns 0 counts unless the queue was created using WithStats.
+// Any thread may call WrapCounts.
+func (q *Queue[T]) WrapCounts() (producer, consumer uint64) {
+	return atomic.LoadUint64(&q.producerWraps), atomic.LoadUint64(&q.consumerWraps)
+}
+
+// SpinStats returns the total number of times the producer and the consumer have spun, i.e.
+// invoked the wait strategy, while blocked in Push and Pop and the operations building on them,
+// over the lifetime of the queue. High counts indicate that the queue is undersized or that the
+// consumer does not keep up, and are an input for choosing a WaitStrategy. WithSpinInstrumentation
+// provides the distribution of spins per operation instead. SpinStats returns 0 counts unless the
+// queue was created using WithStats.
+// Any thread may call SpinStats.
+func (q *Queue[T]) SpinStats() (producerSpins, consumerSpins uint64) {
+	return atomic.LoadUint64(&q.producerWaits), atomic.LoadUint64(&q.consumerWaits)
+}
+
+// HighWaterMark returns the largest number of elements the queue has held. HighWaterMark returns 0
+// unless the queue was created using WithStats.
+// Any thread may call HighWaterMark.
+func (q *Queue[T]) HighWaterMark() uint64 {
+	return atomic.LoadUint64(&q.highWater)
+}
+
+// Stats returns the length, capacity and high-water mark of the queue in a single call. As with
+// Len, the returned length is only a snapshot and may be outdated by the time it is used; it is
+// however guaranteed not to exceed the capacity. The high-water mark is 0 unless the queue was
+// created using WithStats.
+// Any thread may call Stats.
+func (q *Queue[T]) Stats() (length, capacity, high uint64) {
+	rIdx := atomic.LoadUint64(&q.rIdx)
+	wIdx := atomic.LoadUint64(&q.wIdx)
+	high = atomic.LoadUint64(&q.highWater)
+
+	return q.observedLength(rIdx, wIdx), q.Cap(), high
+}
+
+// Pressure returns the fill fraction of the queue, i.e. Len divided by Cap, between 0 for an empty
+// and 1 for a full queue. A queue of capacity 0 is always full, so its pressure is 1. As with Len,
+// the result is only a snapshot.
+// Any thread may call Pressure.
+func (q *Queue[T]) Pressure() float64 {
+	return q.pressure(q.Len())
+}
+
+// pressure returns the fill fraction of the queue when it holds `length` elements.
+func (q *Queue[T]) pressure(length uint64) float64 {
+	if q.Cap() == 0 {
+		return 1
+	}
+
+	return float64(length) / float64(q.Cap())
+}
+
+// SizeBytes returns the approximate number of bytes of memory occupied by the queue: the underlying
+// buffer, the queue structure itself including its padding, and any bookkeeping allocated by
+// options. Memory referenced by the elements, e.g. through pointers, is not included.
+// Any thread may call SizeBytes.
+func (q *Queue[T]) SizeBytes() uintptr {
+	var zero T
+	size := unsafe.Sizeof(*q) + uintptr(len(q.items))*unsafe.Sizeof(zero)
+	size += uintptr(len(q.filled)+len(q.completed)+len(q.occupied)) * unsafe.Sizeof(false)
+	size += uintptr(len(q.dwell)) * unsafe.Sizeof(int64(0))
+	if q.producerSpins != nil {
+		size += 2 * unsafe.Sizeof(spinHistogram{})
+	}
+
+	return size
+}
+
+// Positions returns the current read and write indices of the queue. By default the indices are
+// positions in the underlying buffer of Cap()+1 slots and wrap around to 0 at its end; with
+// WithMonotonicIndices they count the elements ever removed and added, wrapping around only at
+// the uint64 boundary. The number of elements in the queue is the distance from the read to the
+// write index. Both indices are loaded in a single call, but not as one atomic snapshot.
+// Any thread may call Positions.
+func (q *Queue[T]) Positions() (read, write uint64) {
+	read = atomic.LoadUint64(&q.rIdx)
+	write = atomic.LoadUint64(&q.wIdx)
+
+	return read, write
+}
+
+// Layout describes where the elements and the free slots of the queue are located in its
+// underlying buffer of Cap()+1 slots, e.g. for visualising the state of the queue. The elements
+// start at slot `usedStart`, and `usedLen` of them follow contiguously before the end of the buffer.
+// Likewise, the free slots available to the producer start at slot `freeStart`, and `freeLen` of
+// them are contiguous. Either region continues at the start of the buffer if it wraps around; the
+// total number of elements is Len. When called by a thread other than the producer and the
+// consumer, the result is only a snapshot, which loads both indices but not atomically.
+// Any thread may call Layout.
+func (q *Queue[T]) Layout() (usedStart, usedLen, freeStart, freeLen uint64) {
+	rIdx, wIdx := q.Positions()
+	n := q.observedLength(rIdx, wIdx)
+	size := uint64(len(q.items))
+
+	usedStart, usedLen = q.slot(rIdx), n
+	if end := size - usedStart; usedLen > end {
+		usedLen = end
+	}
+	freeStart, freeLen = q.slot(wIdx), q.Cap()-n
+	if end := size - freeStart; freeLen > end {
+		freeLen = end
+	}
+
+	return usedStart, usedLen, freeStart, freeLen
+}
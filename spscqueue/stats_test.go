@@ -0,0 +1,314 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// Test for the high-water mark.
+func TestHighWaterMark(t *testing.T) {
+	q := New[int](8, WithStats())
+	for i := 0; i < 5; i++ {
+		q.Push(i)
+	}
+	for i := 0; i < 3; i++ {
+		q.Pop()
+	}
+	q.Offer(5)
+	if h := q.HighWaterMark(); h != 5 {
+		t.Errorf("Unexpected high-water mark; %v != 5", h)
+	}
+	for i := 0; i < 5; i++ {
+		q.Push(i)
+	}
+	if h := q.HighWaterMark(); h != 8 {
+		t.Errorf("Unexpected high-water mark; %v != 8", h)
+	}
+
+	q = New[int](8)
+	q.Push(1)
+	if h := q.HighWaterMark(); h != 0 {
+		t.Errorf("Unexpected high-water mark; %v != 0", h)
+	}
+}
+
+// Test that Stats reports an exact capacity and a bounded length while the queue is in use.
+func TestStats(t *testing.T) {
+	const numItems = 10000
+	q := New[int](16, WithStats())
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	if l, c, h := q.Stats(); l != 0 || c != 16 || h != 0 {
+		t.Errorf("Unexpected stats; (%v, %v, %v) != (0, 16, 0)", l, c, h)
+	}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for !isDone(done) {
+			l, c, h := q.Stats()
+			if c != 16 {
+				t.Errorf("Unexpected capacity; %v != 16", c)
+			}
+			if l > c || h > c {
+				t.Errorf("Length or high-water mark exceeds capacity; %v, %v > %v", l, h, c)
+			}
+			runtime.Gosched()
+		}
+	}(&wg)
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			q.Push(i)
+		}
+	}(&wg)
+
+	for i := 0; i < numItems; i++ {
+		q.Pop()
+	}
+	close(done)
+	wg.Wait()
+
+	if l, _, _ := q.Stats(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// Test that every length-derived value stays within [0, Cap()] while observers race with the
+// producer and the consumer, for each indexing scheme.
+func TestObservedLengthBounds(t *testing.T) {
+	const numItems = 20000
+	for _, c := range []struct {
+		name string
+		size uint
+		opts []Option
+	}{
+		{"wrapping", 3, []Option{WithStats()}},
+		{"monotonic", 5, []Option{WithStats(), WithMonotonicIndices()}},
+		{"monotonic-pow2", 7, []Option{WithStats(), WithMonotonicIndices()}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			q := New[int](c.size, c.opts...)
+			done := make(chan struct{})
+			wg := sync.WaitGroup{}
+
+			for i := 0; i < 2; i++ {
+				wg.Add(1)
+				go func(wg *sync.WaitGroup) {
+					defer wg.Done()
+					for !isDone(done) {
+						capacity := q.Cap()
+						if l := q.Len(); l > capacity {
+							t.Errorf("Length exceeds capacity; %v > %v", l, capacity)
+						}
+						if l, _, h := q.Stats(); l > capacity || h > capacity {
+							t.Errorf("Length or high-water mark exceeds capacity; %v, %v > %v",
+								l, h, capacity)
+						}
+						runtime.Gosched()
+					}
+				}(&wg)
+			}
+
+			wg.Add(1)
+			go func(wg *sync.WaitGroup) {
+				defer wg.Done()
+				for i := 0; i < numItems; i++ {
+					q.Push(i)
+				}
+			}(&wg)
+
+			for i := 0; i < numItems; i++ {
+				q.Pop()
+			}
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// Test that the wrap counts match the number of passes through the underlying buffer.
+func TestWrapCounts(t *testing.T) {
+	for _, opts := range [][]Option{{WithStats()}, {WithStats(), WithMonotonicIndices()}} {
+		q := New[int](4, opts...)
+
+		// The underlying buffer has 5 slots; cycle through it 3 times one element at a time.
+		for i := 0; i < 15; i++ {
+			q.Push(i)
+			q.Pop()
+		}
+		if p, c := q.WrapCounts(); p != 3 || c != 3 {
+			t.Errorf("Unexpected wrap counts; (%v, %v) != (3, 3)", p, c)
+		}
+
+		// Spans crossing the end of the buffer wrap once; the elements are in slots 0 to 3.
+		q.PushSlice([]int{0, 1, 2, 3})
+		q.AdvanceN(4)
+		if p, c := q.WrapCounts(); p != 3 || c != 3 {
+			t.Errorf("Unexpected wrap counts; (%v, %v) != (3, 3)", p, c)
+		}
+		q.PushSlice([]int{4, 5})
+		q.AdvanceN(uint64(len(q.ReadableSpan())))
+		q.AdvanceN(uint64(len(q.ReadableSpan())))
+		if p, c := q.WrapCounts(); p != 4 || c != 4 {
+			t.Errorf("Unexpected wrap counts; (%v, %v) != (4, 4)", p, c)
+		}
+	}
+
+	q := New[int](4)
+	for i := 0; i < 15; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+	if p, c := q.WrapCounts(); p != 0 || c != 0 {
+		t.Errorf("Got wrap counts without stats; (%v, %v)", p, c)
+	}
+}
+
+// Test that the pressure reported with the front element matches Len()/Cap().
+func TestFrontAndPressure(t *testing.T) {
+	q := New[int](4)
+	if p := q.Pressure(); p != 0 {
+		t.Errorf("Unexpected pressure; %v != 0", p)
+	}
+	if v, p, ok := q.FrontAndPressure(); ok || p != 0 || v != 0 {
+		t.Errorf("Got element of empty queue; %v, %v", v, p)
+	}
+
+	for i := 1; i <= 4; i++ {
+		q.Push(i)
+		want := float64(q.Len()) / float64(q.Cap())
+		v, p, ok := q.FrontAndPressure()
+		if !ok || v != 1 {
+			t.Errorf("Got incorrect value; %v != 1", v)
+		}
+		if p != want || q.Pressure() != want {
+			t.Errorf("Unexpected pressure; %v, %v != %v", p, q.Pressure(), want)
+		}
+	}
+
+	if p := New[int](0).Pressure(); p != 1 {
+		t.Errorf("Unexpected pressure of zero-capacity queue; %v != 1", p)
+	}
+}
+
+// Test that the reported memory footprint scales linearly with the capacity.
+func TestSizeBytes(t *testing.T) {
+	type element [3]uint64
+	base := New[element](0).SizeBytes()
+	elSize := unsafe.Sizeof(element{})
+	for _, size := range []uint{1, 10, 100, 1000} {
+		if s := New[element](size).SizeBytes(); s != base+uintptr(size)*elSize {
+			t.Errorf("Unexpected size for capacity %v; %v != %v", size, s, base+uintptr(size)*elSize)
+		}
+	}
+
+	if s := New[element](10, WithSpinInstrumentation()).SizeBytes(); s <= New[element](10).SizeBytes() {
+		t.Errorf("Instrumented queue not larger than plain queue; %v", s)
+	}
+}
+
+// Test for the reported read and write positions.
+func TestPositions(t *testing.T) {
+	q := New[int](4)
+	if r, w := q.Positions(); r != 0 || w != 0 {
+		t.Errorf("Unexpected positions; (%v, %v) != (0, 0)", r, w)
+	}
+	for i := 0; i < 3; i++ {
+		q.Push(i)
+	}
+	q.Pop()
+	if r, w := q.Positions(); r != 1 || w != 3 {
+		t.Errorf("Unexpected positions; (%v, %v) != (1, 3)", r, w)
+	}
+
+	// The indices wrap around at the end of the underlying buffer.
+	q.Pop()
+	for i := 0; i < 3; i++ {
+		q.Push(i)
+	}
+	if r, w := q.Positions(); r != 2 || w != 1 {
+		t.Errorf("Unexpected positions; (%v, %v) != (2, 1)", r, w)
+	}
+
+	m := New[int](4, WithMonotonicIndices())
+	for i := 0; i < 12; i++ {
+		m.Push(i)
+		m.Pop()
+	}
+	m.Push(12)
+	if r, w := m.Positions(); r != 12 || w != 13 {
+		t.Errorf("Unexpected positions; (%v, %v) != (12, 13)", r, w)
+	}
+}
+
+// Test that Layout reports the regions of the underlying buffer as the elements wrap around.
+func TestLayout(t *testing.T) {
+	q := New[int](5)
+	type layout struct{ usedStart, usedLen, freeStart, freeLen uint64 }
+	check := func(want layout) {
+		t.Helper()
+		var got layout
+		got.usedStart, got.usedLen, got.freeStart, got.freeLen = q.Layout()
+		if got != want {
+			t.Errorf("Unexpected layout; %+v != %+v", got, want)
+		}
+	}
+
+	check(layout{0, 0, 0, 5})
+	for i := 0; i < 4; i++ {
+		q.Push(i)
+	}
+	check(layout{0, 4, 4, 1})
+	q.AdvanceN(3)
+	check(layout{3, 1, 4, 2})
+
+	// The elements now wrap around the end of the buffer.
+	for i := 4; i < 8; i++ {
+		q.Push(i)
+	}
+	check(layout{3, 3, 2, 0})
+	q.AdvanceN(3)
+	check(layout{0, 2, 2, 3})
+}
+
+// Test that the spin counters record the waiting of a producer and a consumer which run apart.
+func TestSpinStats(t *testing.T) {
+	const numItems = 20
+	if p, c := New[int](1).SpinStats(); p != 0 || c != 0 {
+		t.Errorf("Got spin counts from queue without stats; %v, %v", p, c)
+	}
+
+	q := New[int](1, WithStats())
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			q.Push(i)
+		}
+		// Let the consumer wait for the final element.
+		time.Sleep(time.Millisecond)
+		q.Push(numItems)
+	}(&wg)
+
+	// Let the producer fill the queue and block until the consumer starts.
+	time.Sleep(time.Millisecond)
+	for i := 0; i <= numItems; i++ {
+		if v := q.Pop(); v != i {
+			t.Errorf("Got incorrect value; %v != %v", v, i)
+		}
+	}
+	wg.Wait()
+
+	if p, c := q.SpinStats(); p == 0 || c == 0 {
+		t.Errorf("Unexpected spin counts; %v, %v", p, c)
+	}
+}
@@ -0,0 +1,61 @@
+package spscqueue
+
+import (
+	"os"
+	"unsafe"
+)
+
+// Role identifies a side of the queue.
+type Role int
+
+const (
+	// RoleProducer is the side adding elements to the queue.
+	RoleProducer Role = iota + 1
+	// RoleConsumer is the side removing elements from the queue.
+	RoleConsumer
+)
+
+// TouchFromProducer writes to every page of the underlying buffer of a queue created using
+// WithFirstTouch(RoleProducer), and does nothing otherwise. The operating system backs a large
+// buffer with physical pages only when they are first written, and the page faults, cache fills
+// and TLB entries this causes are incurred by whichever thread writes first; by default that is
+// whichever side of the queue happens to reach each page first. Touching the buffer up front from
+// the side whose latency matters most moves those costs out of the steady state, towards the
+// processor that side runs on. Small buffers share pages with other allocations, so touching them
+// has little effect.
+// TouchFromProducer overwrites the buffer with zero values, so it must be called before the queue
+// is used and before Fill.
+// TouchFromProducer should be called by the producer.
+func (q *Queue[T]) TouchFromProducer() {
+	q.checkProducer()
+	if q.firstTouch == RoleProducer {
+		q.touch()
+	}
+}
+
+// TouchFromConsumer writes to every page of the underlying buffer of a queue created using
+// WithFirstTouch(RoleConsumer), and does nothing otherwise. See TouchFromProducer for details.
+// TouchFromConsumer must be called before the queue is used and before Fill.
+// TouchFromConsumer should be called by the consumer.
+func (q *Queue[T]) TouchFromConsumer() {
+	q.checkConsumer()
+	if q.firstTouch == RoleConsumer {
+		q.touch()
+	}
+}
+
+// touch writes the zero value to one element per page of the underlying buffer.
+func (q *Queue[T]) touch() {
+	var zero T
+	size := int(unsafe.Sizeof(zero))
+	if size == 0 {
+		return
+	}
+	stride := os.Getpagesize() / size
+	if stride == 0 {
+		stride = 1
+	}
+	for i := 0; i < len(q.items); i += stride {
+		q.items[i] = zero
+	}
+}
@@ -0,0 +1,40 @@
+package spscqueue
+
+import (
+	"sync"
+	"testing"
+)
+
+// Test for touching the underlying buffer from either side.
+func TestFirstTouch(t *testing.T) {
+	const numItems = 10000
+	for _, role := range []Role{RoleProducer, RoleConsumer} {
+		q := New[[3]int](4096, WithFirstTouch(role))
+		wg := sync.WaitGroup{}
+		touched := make(chan struct{})
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			q.TouchFromConsumer()
+			close(touched)
+			for i := 0; i < numItems; i++ {
+				if v := q.Pop(); v[0] != i {
+					t.Errorf("Got incorrect value; %v != %v", v[0], i)
+				}
+			}
+		}(&wg)
+
+		wg.Add(1)
+		go func(wg *sync.WaitGroup) {
+			defer wg.Done()
+			q.TouchFromProducer()
+			<-touched
+			for i := 0; i < numItems; i++ {
+				q.Push([3]int{i})
+			}
+		}(&wg)
+
+		wg.Wait()
+	}
+}
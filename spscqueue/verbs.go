@@ -0,0 +1,55 @@
+package spscqueue
+
+// The methods in this file provide names familiar from java.util.Queue and BlockingQueue on top of
+// the native queue operations:
+//
+//	Verb     Role      Blocking  Equivalent
+//	Put      producer  no        Offer
+//	MustPush producer  yes       Push, panicking if the queue is closed
+//	Peek     consumer  no        Front
+//	Poll     consumer  no        Front followed by Advance
+//	Take     consumer  yes       Pop
+
+// Put is equivalent to Offer. It adds the passed element to the queue if there is an available
+// slot and the queue has not been closed, and returns whether the element was added.
+// Put should be called by the producer.
+func (q *Queue[T]) Put(el T) bool {
+	return q.Offer(el)
+}
+
+// MustPush adds the passed element to the queue, blocking until a slot is available like Push. It
+// panics if the queue has been closed, which indicates a programming error.
+// MustPush should be called by the producer.
+func (q *Queue[T]) MustPush(el T) {
+	if q.closed != 0 {
+		panic(ErrClosed)
+	}
+	q.Push(el)
+}
+
+// Peek is equivalent to Front. It returns the oldest element in the queue without removing it, and
+// false if the queue is empty.
+// Peek should be called by the consumer.
+func (q *Queue[T]) Peek() (T, bool) {
+	return q.Front()
+}
+
+// Poll is a non-blocking variant of Pop. It returns and removes the oldest element in the queue,
+// and returns false if the queue is empty. It is equivalent to Front followed by Advance on
+// success.
+// Poll should be called by the consumer.
+func (q *Queue[T]) Poll() (T, bool) {
+	el, ok := q.Front()
+	if ok {
+		q.Advance()
+	}
+
+	return el, ok
+}
+
+// Take is equivalent to Pop. It returns and removes the oldest element in the queue, blocking until
+// one is available.
+// Take should be called by the consumer.
+func (q *Queue[T]) Take() T {
+	return q.Pop()
+}
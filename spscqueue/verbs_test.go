@@ -0,0 +1,96 @@
+package spscqueue
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test for the Peek, Poll and Take aliases.
+func TestPeekPollTake(t *testing.T) {
+	q := New[int](4)
+	if _, ok := q.Peek(); ok {
+		t.Error("Peeked element of empty queue")
+	}
+	if _, ok := q.Poll(); ok {
+		t.Error("Polled element of empty queue")
+	}
+
+	q.Push(1)
+	q.Push(2)
+	q.Push(3)
+	for i := 0; i < 2; i++ {
+		if v, ok := q.Peek(); !ok || v != 1 {
+			t.Errorf("Got incorrect value; %v != 1", v)
+		}
+	}
+	if l := q.Len(); l != 3 {
+		t.Errorf("Peek advanced the queue; length %v != 3", l)
+	}
+
+	if v, ok := q.Poll(); !ok || v != 1 {
+		t.Errorf("Got incorrect value; %v != 1", v)
+	}
+	if l := q.Len(); l != 2 {
+		t.Errorf("Poll did not advance the queue; length %v != 2", l)
+	}
+	if v := q.Take(); v != 2 {
+		t.Errorf("Got incorrect value; %v != 2", v)
+	}
+	if v, ok := q.Peek(); !ok || v != 3 {
+		t.Errorf("Got incorrect value; %v != 3", v)
+	}
+}
+
+// Test the blocking behaviour of each verb.
+func TestVerbBlocking(t *testing.T) {
+	q := New[int](1)
+	if !q.Put(1) {
+		t.Error("Failed to put element into empty queue")
+	}
+	if q.Put(2) {
+		t.Error("Managed to put element into full queue!")
+	}
+
+	// The test goroutine is the producer, and a single goroutine is the consumer.
+	var pushed int32
+	taken := make(chan int)
+	go func() {
+		// MustPush blocks until Take frees a slot.
+		time.Sleep(10 * time.Millisecond)
+		if atomic.LoadInt32(&pushed) != 0 {
+			t.Error("MustPush returned while queue was full")
+		}
+		if v := q.Take(); v != 1 {
+			t.Errorf("Got incorrect value; %v != 1", v)
+		}
+		if v := q.Take(); v != 2 {
+			t.Errorf("Got incorrect value; %v != 2", v)
+		}
+		taken <- q.Take()
+	}()
+	q.MustPush(2)
+	atomic.StoreInt32(&pushed, 1)
+
+	// Take blocks until an element is available.
+	select {
+	case v := <-taken:
+		t.Errorf("Take returned %v from empty queue", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+	q.Put(3)
+	if v := <-taken; v != 3 {
+		t.Errorf("Got incorrect value; %v != 3", v)
+	}
+
+	q.Close()
+	if q.Put(4) {
+		t.Error("Managed to put element into closed queue!")
+	}
+	defer func() {
+		if r := recover(); r != ErrClosed {
+			t.Errorf("Unexpected panic value; %v != %v", r, ErrClosed)
+		}
+	}()
+	q.MustPush(4)
+}
@@ -0,0 +1,207 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// WaitStrategy determines how a blocked producer or consumer waits for the other side to make
+// progress. Wait is called each time a blocking operation finds that it still cannot proceed;
+// `spins` is the number of times Wait has already been called during the current operation.
+// Implementations may be called concurrently by the producer and the consumer.
+type WaitStrategy interface {
+	Wait(spins uint64)
+}
+
+// YieldWait is a WaitStrategy which yields the processor to other goroutines using runtime.Gosched.
+type YieldWait struct{}
+
+// Wait implements WaitStrategy.
+func (YieldWait) Wait(uint64) {
+	runtime.Gosched()
+}
+
+// DefaultAdaptiveSpins is the number of waits for which the default wait strategy spins before it
+// starts yielding the processor.
+const DefaultAdaptiveSpins = 16
+
+// DefaultAdaptiveYields is the number of waits for which the default wait strategy yields the
+// processor after spinning, before it starts sleeping.
+const DefaultAdaptiveYields = 128
+
+// DefaultAdaptiveSleep is the duration for which the default wait strategy sleeps per wait once it
+// has run out of yields.
+const DefaultAdaptiveSleep = 20 * time.Microsecond
+
+// defaultWaitStrategy is the WaitStrategy used unless another one is configured.
+var defaultWaitStrategy = AdaptiveWait{
+	Spins:  DefaultAdaptiveSpins,
+	Yields: DefaultAdaptiveYields,
+	Sleep:  DefaultAdaptiveSleep,
+}
+
+// pauseIterations is the number of times pause is called per spin.
+const pauseIterations = 8
+
+// multiCPU indicates whether the other side of a queue may run in parallel, in which case spinning
+// can be worthwhile.
+var multiCPU = runtime.NumCPU() > 1
+
+// AdaptiveWait is a WaitStrategy which briefly spins before falling back to yielding the processor
+// using runtime.Gosched, and eventually to sleeping. The first Spins waits of an operation merely
+// pause for a few cycles, which avoids paying for a full Gosched when the other side is only a
+// moment away from making progress. The following Yields waits call runtime.Gosched. Every wait
+// thereafter sleeps for Sleep, which guarantees that the other side gets to run even when the
+// scheduler is starved and Gosched keeps picking the waiting goroutine; if Sleep is 0, AdaptiveWait
+// keeps yielding instead. Since the count restarts with each operation, AdaptiveWait returns to
+// spinning as soon as the queue makes progress. On single-CPU machines AdaptiveWait yields instead
+// of spinning, since the other side cannot make progress while the caller spins.
+// AdaptiveWait{DefaultAdaptiveSpins, DefaultAdaptiveYields, DefaultAdaptiveSleep} is the default
+// wait strategy.
+type AdaptiveWait struct {
+	Spins  uint64
+	Yields uint64
+	Sleep  time.Duration
+}
+
+// Wait implements WaitStrategy.
+func (a AdaptiveWait) Wait(spins uint64) {
+	switch {
+	case a.Sleep > 0 && spins >= a.Spins+a.Yields:
+		time.Sleep(a.Sleep)
+	case spins >= a.Spins || !multiCPU:
+		runtime.Gosched()
+	default:
+		for i := 0; i < pauseIterations; i++ {
+			pause()
+		}
+	}
+}
+
+// maxSpinPauseShift bounds the number of times SpinWait calls pause per wait to 1<<maxSpinPauseShift.
+const maxSpinPauseShift = 4
+
+// SpinWait is a WaitStrategy which busy-spins without yielding the processor. It offers the lowest
+// latency when the producer and consumer run on dedicated processors, at the cost of occupying a
+// processor while waiting. Each wait of an operation issues twice as many processor spin hints
+// (PAUSE on amd64, YIELD on arm64) as the previous one, up to 16, so that a short wait stays
+// responsive while a long one eases off the memory bus.
+type SpinWait struct{}
+
+// Wait implements WaitStrategy.
+func (SpinWait) Wait(spins uint64) {
+	if spins > maxSpinPauseShift {
+		spins = maxSpinPauseShift
+	}
+	for i := 0; i < 1<<spins; i++ {
+		pause()
+	}
+}
+
+// BackoffWait is a WaitStrategy which sleeps while waiting, starting at Min and doubling the sleep
+// duration on each subsequent wait of the same operation, up to Max. It keeps the processor free
+// at the cost of wake-up latency.
+type BackoffWait struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Wait implements WaitStrategy.
+func (b BackoffWait) Wait(spins uint64) {
+	d := b.Min
+	for i := uint64(0); i < spins && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	time.Sleep(d)
+}
+
+// waitStrategyBox wraps WaitStrategy values so that differing implementations can be stored in the
+// same atomic.Value.
+type waitStrategyBox struct {
+	s WaitStrategy
+}
+
+// SetWaitStrategy replaces the WaitStrategy used by blocking operations. The new strategy takes
+// effect on the next wait; an operation which is already blocked picks it up before it next waits.
+// Any thread may call SetWaitStrategy.
+func (q *Queue[T]) SetWaitStrategy(s WaitStrategy) {
+	q.wait.Store(waitStrategyBox{s})
+}
+
+// waitStrategy returns the WaitStrategy currently in use.
+func (q *Queue[T]) waitStrategy() WaitStrategy {
+	return q.wait.Load().(waitStrategyBox).s
+}
+
+// awaitConsumer blocks until the producer can move to `wIdxNext` without running into the
+// consumer, and returns the number of times it had to spin. It is called by the producer once its
+// cached read index indicates that the queue is full.
+func (q *Queue[T]) awaitConsumer(wIdxNext uint64) uint64 {
+	q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	if !q.collides(wIdxNext, q.rIdxCached) {
+		return 0
+	}
+
+	var start time.Time
+	if q.blockCallback != nil {
+		start = time.Now()
+	}
+
+	var spins uint64
+	var blocked time.Time
+	for q.collides(wIdxNext, q.rIdxCached) {
+		if q.deadlock > 0 {
+			q.checkDeadlock(RoleProducer, spins, &blocked)
+		}
+		if q.futex != nil {
+			q.futex.notFull.await(func() bool {
+				return !q.collides(wIdxNext, atomic.LoadUint64(&q.rIdx))
+			})
+		} else {
+			q.waitStrategy().Wait(spins)
+		}
+		spins++
+		q.rIdxCached = atomic.LoadUint64(&q.rIdx)
+	}
+	if q.stats {
+		atomic.StoreUint64(&q.producerWaits, q.producerWaits+spins)
+	}
+
+	if q.blockCallback != nil {
+		q.blockCallback(time.Since(start))
+	}
+
+	return spins
+}
+
+// awaitProducer blocks until an element is available at the front of the queue, and returns the
+// number of times it had to spin. It is called by the consumer once its cached write index
+// indicates that the queue is empty.
+func (q *Queue[T]) awaitProducer() uint64 {
+	var spins uint64
+	var blocked time.Time
+	q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	for q.rIdx == q.wIdxCached {
+		if q.deadlock > 0 {
+			q.checkDeadlock(RoleConsumer, spins, &blocked)
+		}
+		if q.futex != nil {
+			q.futex.notEmpty.await(func() bool {
+				return q.rIdx != atomic.LoadUint64(&q.wIdx)
+			})
+		} else {
+			q.waitStrategy().Wait(spins)
+		}
+		spins++
+		q.wIdxCached = atomic.LoadUint64(&q.wIdx)
+	}
+	if q.stats {
+		atomic.StoreUint64(&q.consumerWaits, q.consumerWaits+spins)
+	}
+
+	return spins
+}
@@ -0,0 +1,186 @@
+package spscqueue
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test that the block callback fires when the producer has to wait for a slow consumer.
+func TestBlockCallback(t *testing.T) {
+	const numItems = 10
+	var blocked int
+	var total time.Duration
+	q := New[int](2, WithBlockCallback(func(d time.Duration) {
+		if d <= 0 {
+			t.Errorf("Unexpected block duration; %v <= 0", d)
+		}
+		blocked++
+		total += d
+	}))
+	wg := sync.WaitGroup{}
+
+	wg.Add(1)
+	go func(wg *sync.WaitGroup) {
+		defer wg.Done()
+		for i := 0; i < numItems; i++ {
+			time.Sleep(time.Millisecond)
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}(&wg)
+
+	for i := 0; i < numItems; i++ {
+		q.Push(i)
+	}
+	wg.Wait()
+
+	if blocked == 0 {
+		t.Error("Block callback never fired")
+	}
+	if total < time.Millisecond {
+		t.Errorf("Unexpected total block duration; %v < %v", total, time.Millisecond)
+	}
+
+	// The callback must not fire if the producer never has to wait.
+	blocked = 0
+	q.Push(1)
+	q.Push(2)
+	if blocked != 0 {
+		t.Errorf("Block callback fired without waiting; %v", blocked)
+	}
+}
+
+// countingWait is a WaitStrategy which counts its invocations before yielding.
+type countingWait struct {
+	calls *uint64
+}
+
+func (c countingWait) Wait(uint64) {
+	atomic.AddUint64(c.calls, 1)
+	runtime.Gosched()
+}
+
+// Test for swapping the wait strategy while the consumer is blocked.
+func TestSetWaitStrategy(t *testing.T) {
+	var first, second uint64
+	q := New[int](4, WithWaitStrategy(countingWait{&first}))
+	popped := make(chan int)
+
+	go func() {
+		popped <- q.Pop()
+	}()
+
+	for atomic.LoadUint64(&first) == 0 {
+		runtime.Gosched()
+	}
+	q.SetWaitStrategy(countingWait{&second})
+	for atomic.LoadUint64(&second) == 0 {
+		runtime.Gosched()
+	}
+	before := atomic.LoadUint64(&first)
+
+	q.Push(1)
+	if v := <-popped; v != 1 {
+		t.Errorf("Got incorrect value; %v != 1", v)
+	}
+	if n := atomic.LoadUint64(&first); n != before {
+		t.Errorf("Replaced wait strategy still in use; %v != %v", n, before)
+	}
+
+	// The built-in strategies must all allow the queue to make progress. Each strategy gets a fresh
+	// queue, so that each role stays on a single goroutine.
+	for _, s := range []WaitStrategy{YieldWait{}, AdaptiveWait{Spins: 4}, SpinWait{}, BackoffWait{time.Microsecond, time.Millisecond}} {
+		q := New[int](4)
+		q.SetWaitStrategy(s)
+		go func() {
+			for i := 0; i < 100; i++ {
+				q.Push(i)
+			}
+		}()
+		for i := 0; i < 100; i++ {
+			if v := q.Pop(); v != i {
+				t.Errorf("Got incorrect value; %v != %v", v, i)
+			}
+		}
+	}
+}
+
+// Test that the default wait strategy bounds the latency of a blocked Pop when the scheduler is
+// oversubscribed with busy goroutines.
+func TestStarvationLatency(t *testing.T) {
+	const numItems = 50
+	var stop uint32
+	defer atomic.StoreUint32(&stop, 1)
+	for i := 0; i < 4*runtime.GOMAXPROCS(0); i++ {
+		go func() {
+			for atomic.LoadUint32(&stop) == 0 {
+				// Keep the loop body free of calls, such that the goroutine can only be
+				// preempted asynchronously.
+				for j := 0; j < 1<<20; j++ {
+				}
+			}
+		}()
+	}
+
+	q := New[time.Time](4)
+	go func() {
+		for i := 0; i < numItems; i++ {
+			time.Sleep(time.Millisecond)
+			q.Push(time.Now())
+		}
+	}()
+
+	var worst time.Duration
+	for i := 0; i < numItems; i++ {
+		if d := time.Since(q.Pop()); d > worst {
+			worst = d
+		}
+	}
+	if worst > 500*time.Millisecond {
+		t.Errorf("Unexpected worst-case pop latency; %v > 500ms", worst)
+	}
+	t.Logf("Worst-case pop latency: %v", worst)
+}
+
+// benchmarkLatency measures the round-trip latency between two goroutines ping-ponging elements
+// through a pair of queues using the passed wait strategy, and reports its median and 99th
+// percentile.
+func benchmarkLatency(b *testing.B, s WaitStrategy) {
+	ping := New[int](1, WithWaitStrategy(s))
+	pong := New[int](1, WithWaitStrategy(s))
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		for i := 0; i < b.N; i++ {
+			pong.Push(ping.Pop())
+		}
+	}()
+
+	latencies := make([]time.Duration, b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		ping.Push(i)
+		pong.Pop()
+		latencies[i] = time.Since(start)
+	}
+	b.StopTimer()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	b.ReportMetric(float64(latencies[len(latencies)/2]), "p50-ns")
+	b.ReportMetric(float64(latencies[len(latencies)*99/100]), "p99-ns")
+}
+
+// Round-trip latency benchmarks for the yielding and the adaptive wait strategies.
+func BenchmarkLatencyYield(b *testing.B) {
+	benchmarkLatency(b, YieldWait{})
+}
+
+func BenchmarkLatencyAdaptive(b *testing.B) {
+	benchmarkLatency(b, defaultWaitStrategy)
+}
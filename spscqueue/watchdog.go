@@ -0,0 +1,78 @@
+package spscqueue
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// watchdogSamples is the number of times per stall threshold the watchdog samples the queue.
+const watchdogSamples = 4
+
+// WithStallWatchdog starts a goroutine which watches the consumer of the queue, and calls `onStall`
+// if the consumer has not removed an element for `maxStall` while the queue was not empty. The
+// callback is passed the time since the consumer last made progress, and runs on the watchdog's
+// goroutine; it fires again after each further `maxStall` that the stall persists. If `onStall` is
+// nil, the watchdog panics instead, so that a deadlocked or dead consumer fails loudly. The queue
+// is sampled several times per `maxStall`, so stalls are detected with a delay of up to a quarter
+// of `maxStall`. The watchdog exits once the queue has been closed, even if elements remain in it,
+// so that a consumer shutting down after the producer is not reported as stalled, or once
+// StopWatchdog has been called. Until then its goroutine keeps the queue reachable, so a queue
+// using WithStallWatchdog which is no longer needed must be closed, or its watchdog stopped, for it
+// to be garbage collected.
+func WithStallWatchdog(maxStall time.Duration, onStall func(time.Duration)) Option {
+	return func(o *options) {
+		o.maxStall = maxStall
+		o.onStall = onStall
+	}
+}
+
+// StopWatchdog stops the stall watchdog started through WithStallWatchdog, if any. The watchdog
+// exits the next time it samples the queue. StopWatchdog may be called repeatedly.
+// Any thread may call StopWatchdog.
+func (q *Queue[T]) StopWatchdog() {
+	if q.watchStop == nil {
+		return
+	}
+	q.watchOnce.Do(func() { close(q.watchStop) })
+}
+
+// watch runs the stall watchdog of the queue until it has been closed or StopWatchdog is called.
+func (q *Queue[T]) watch(maxStall time.Duration, onStall func(time.Duration)) {
+	interval := maxStall / watchdogSamples
+	if interval <= 0 {
+		interval = maxStall
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// `since` is the time the consumer was last seen making progress, or the queue empty.
+	last, since := atomic.LoadUint64(&q.rIdx), time.Now()
+	deadline := since.Add(maxStall)
+	for {
+		select {
+		case <-q.watchStop:
+			return
+		case <-ticker.C:
+		}
+		// The time of the tick itself may be stale if the watchdog was not scheduled in time.
+		now := time.Now()
+		if q.Closed() {
+			return
+		}
+		rIdx, wIdx := q.Positions()
+		if rIdx != last || rIdx == wIdx {
+			last, since = rIdx, now
+			deadline = since.Add(maxStall)
+			continue
+		}
+
+		if !now.Before(deadline) {
+			if onStall == nil {
+				panic(fmt.Sprintf("spscqueue: consumer stalled for %v", now.Sub(since)))
+			}
+			onStall(now.Sub(since))
+			deadline = now.Add(maxStall)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package spscqueue
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Test that the watchdog fires once the consumer stalls, and only then.
+func TestStallWatchdog(t *testing.T) {
+	const maxStall = 20 * time.Millisecond
+	stalls := make(chan time.Duration, 16)
+	before := runtime.NumGoroutine()
+	q := New[int](4, WithStallWatchdog(maxStall, func(d time.Duration) { stalls <- d }))
+
+	// The watchdog must not fire while the queue is empty or the consumer makes progress.
+	time.Sleep(2 * maxStall)
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+		time.Sleep(maxStall / 10)
+		q.Pop()
+	}
+	select {
+	case d := <-stalls:
+		t.Errorf("Watchdog fired without a stall; %v", d)
+	default:
+	}
+
+	// Stop consuming with an element in the queue.
+	start := time.Now()
+	q.Push(1)
+	select {
+	case d := <-stalls:
+		// The queue was last seen empty up to one sampling interval before the push.
+		if elapsed := time.Since(start); d < maxStall || elapsed < maxStall-maxStall/watchdogSamples {
+			t.Errorf("Watchdog fired too early; %v, %v < %v", d, elapsed, maxStall)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watchdog did not fire for a stalled consumer")
+	}
+	// A persisting stall is reported again, with the time since the consumer last made progress.
+	if d := <-stalls; d < 2*maxStall {
+		t.Errorf("Unexpected stall duration; %v < %v", d, 2*maxStall)
+	}
+
+	// Once the queue has been closed and drained, the watchdog exits.
+	q.Pop()
+	q.Close()
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatal("Watchdog did not exit after the queue was closed and drained")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Test that the watchdog exits once the queue is closed, without the consumer draining it.
+func TestStallWatchdogCloseWithoutDrain(t *testing.T) {
+	const maxStall = 5 * time.Millisecond
+	before := runtime.NumGoroutine()
+	// The watchdog would panic, and take down the test binary, if it reported the abandoned
+	// elements as a stall.
+	q := New[int](4, WithStallWatchdog(maxStall, nil))
+	q.Push(1)
+	q.Push(2)
+	q.Close()
+
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatal("Watchdog did not exit after the queue was closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(4 * maxStall)
+}
+
+// Test for stopping the watchdog of a queue which is neither closed nor drained.
+func TestStopWatchdog(t *testing.T) {
+	const maxStall = 5 * time.Millisecond
+	stalls := make(chan time.Duration, 16)
+	before := runtime.NumGoroutine()
+	q := New[int](4, WithStallWatchdog(maxStall, func(d time.Duration) { stalls <- d }))
+	q.Push(1)
+	<-stalls
+
+	q.StopWatchdog()
+	q.StopWatchdog()
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatal("Watchdog did not exit after it was stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// Drain stalls reported before the watchdog saw the stop; none may follow.
+	for len(stalls) > 0 {
+		<-stalls
+	}
+	time.Sleep(4 * maxStall)
+	if n := len(stalls); n != 0 {
+		t.Errorf("Watchdog fired %v times after it was stopped", n)
+	}
+
+	// StopWatchdog is a no-op for queues without a watchdog.
+	New[int](4).StopWatchdog()
+}
+
+// Test that the methods which move the indices of a quiesced queue do not race with the watchdog.
+func TestStallWatchdogQuiesced(t *testing.T) {
+	q := New[int](4, WithStallWatchdog(time.Millisecond, func(time.Duration) {}))
+	data, err := New[int](8).MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to marshal queue; %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		q.Push(i)
+		if err := q.Resize(uint(8 + i)); err != nil {
+			t.Fatalf("Failed to resize queue; %v", err)
+		}
+		time.Sleep(time.Millisecond)
+		if err := q.Restore(1, 2); err != nil {
+			t.Fatalf("Failed to restore queue; %v", err)
+		}
+		time.Sleep(time.Millisecond)
+		q.ResetAndFill(func() int { return 0 })
+		time.Sleep(time.Millisecond)
+		if err := q.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Failed to unmarshal queue; %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	q.Close()
+}
@@ -0,0 +1,37 @@
+package spscqueue
+
+import (
+	"io"
+)
+
+// WriteAllTo writes the bytes currently in a byte queue to `w`, directly from the underlying
+// buffer, and returns the number of bytes written. The bytes are written using at most two calls to
+// w.Write, one per contiguous span as returned by ReadableSpans. Only the bytes accepted by `w` are
+// removed from the queue; if `w` returns an error, WriteAllTo stops and returns it, and if `w`
+// accepts fewer bytes than passed without an error, WriteAllTo returns io.ErrShortWrite.
+// WriteAllTo does not block on the queue.
+// WriteAllTo should be called by the consumer.
+func WriteAllTo(q *Queue[byte], w io.Writer) (int, error) {
+	first, second := q.ReadableSpans()
+
+	var written int
+	for _, span := range [][]byte{first, second} {
+		if len(span) == 0 {
+			continue
+		}
+		// The second span remains valid after moving past the first one, since the producer only
+		// reuses the slots the consumer has freed.
+		n, err := w.Write(span)
+		if n > 0 {
+			q.AdvanceN(uint64(n))
+			written += n
+		}
+		if err != nil {
+			return written, err
+		} else if n < len(span) {
+			return written, io.ErrShortWrite
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,80 @@
+package spscqueue
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// Test for writing wrapped bytes to a writer.
+func TestWriteAllTo(t *testing.T) {
+	q := New[byte](8)
+	var buf bytes.Buffer
+	if n, err := WriteAllTo(q, &buf); n != 0 || err != nil {
+		t.Errorf("Unexpected result for empty queue; %v, %v", n, err)
+	}
+
+	// Move the indices so that the payload wraps around the end of the buffer.
+	for i := 0; i < 6; i++ {
+		q.Push(0)
+	}
+	q.AdvanceN(6)
+
+	const payload = "abcdefg"
+	for _, c := range []byte(payload) {
+		q.Push(c)
+	}
+	if n, err := WriteAllTo(q, &buf); n != len(payload) || err != nil {
+		t.Errorf("Unexpected result; %v, %v != %v, nil", n, err, len(payload))
+	}
+	if got := buf.String(); got != payload {
+		t.Errorf("Got incorrect value; %q != %q", got, payload)
+	}
+	if l := q.Len(); l != 0 {
+		t.Errorf("Unexpected length; %v != 0", l)
+	}
+}
+
+// limitedWriter accepts up to `n` bytes in total, and then fails with `err`.
+type limitedWriter struct {
+	buf bytes.Buffer
+	n   int
+	err error
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.n {
+		w.n -= len(p)
+		return w.buf.Write(p)
+	}
+	n, _ := w.buf.Write(p[:w.n])
+	w.n = 0
+
+	return n, w.err
+}
+
+// Test that only the bytes accepted by the writer are removed from the queue.
+func TestWriteAllToShort(t *testing.T) {
+	errFull := errors.New("full")
+	for _, tc := range []struct {
+		err  error
+		want error
+	}{{errFull, errFull}, {nil, io.ErrShortWrite}} {
+		q := New[byte](8)
+		for _, c := range []byte("abcdef") {
+			q.Push(c)
+		}
+
+		w := &limitedWriter{n: 4, err: tc.err}
+		if n, err := WriteAllTo(q, w); n != 4 || !errors.Is(err, tc.want) {
+			t.Errorf("Unexpected result; %v, %v != 4, %v", n, err, tc.want)
+		}
+		if got := w.buf.String(); got != "abcd" {
+			t.Errorf("Got incorrect value; %q != %q", got, "abcd")
+		}
+		if n, _ := WriteAllTo(q, &w.buf); n != 2 || w.buf.String() != "abcdef" {
+			t.Errorf("Got incorrect remainder; %v, %q", n, w.buf.String())
+		}
+	}
+}